@@ -3,249 +3,231 @@ package main
 import (
 	"battleship/game"
 	"encoding/json"
-	"os"
-	"path/filepath"
+	"time"
 )
 
 type Achievement struct {
 	ID          string
 	Name        string
 	Description string
-	Unlocked    bool
+	Unlock      *Unlock // When and how it was earned, nil while locked
 }
 
-type Achievements struct {
-	PerfectGame     bool `json:"perfect_game"`      // Win without any of your ships sunk
-	Sharpshooter    bool `json:"sharpshooter"`      // Win with 90%+ accuracy
-	ComebackKing    bool `json:"comeback_king"`     // Win after losing 4 ships
-	FirstBlood      bool `json:"first_blood"`       // Win your first game
-	HardcoreVictor  bool `json:"hardcore_victor"`   // Beat Hard difficulty
-	SalvoMaster     bool `json:"salvo_master"`      // Win in Salvo mode
-	Efficient       bool `json:"efficient"`         // Win in under 50 shots
-	LuckyShot       bool `json:"lucky_shot"`        // Sink a ship on first hit
-	Domination      bool `json:"domination"`        // Win with all ships intact
-	SmallBoardWin   bool `json:"small_board_win"`   // Win on 8x8 board
-	LargeBoardWin   bool `json:"large_board_win"`   // Win on 12x12 board
+// Unlocked reports whether the achievement has been earned
+func (a Achievement) Unlocked() bool {
+	return a.Unlock != nil
 }
 
-func LoadAchievements() *Achievements {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return &Achievements{}
-	}
-
-	filePath := filepath.Join(homeDir, ".battleship_achievements.json")
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return &Achievements{}
-	}
-
-	var achievements Achievements
-	if err := json.Unmarshal(data, &achievements); err != nil {
-		return &Achievements{}
-	}
-
-	return &achievements
+// Unlock records when an achievement was earned and the settings of the game
+// that earned it. Achievements carried over from before unlocks were recorded
+// have a zero Date and no settings.
+type Unlock struct {
+	Date       time.Time `json:"date"`
+	Difficulty string    `json:"difficulty,omitempty"`
+	Rows       int       `json:"rows,omitempty"`
+	Cols       int       `json:"cols,omitempty"`
+	Fleet      string    `json:"fleet,omitempty"`
+	Salvo      bool      `json:"salvo,omitempty"`
 }
 
-func (a *Achievements) Save() error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
+// newUnlock records an achievement earned at the given time by g
+func newUnlock(g *game.Game, at time.Time) *Unlock {
+	return &Unlock{
+		Date:       at,
+		Difficulty: g.ComputerStrategy,
+		Rows:       g.Rows,
+		Cols:       g.Cols,
+		Fleet:      g.Fleet.Name,
+		Salvo:      g.SalvoMode,
 	}
-
-	filePath := filepath.Join(homeDir, ".battleship_achievements.json")
-	data, err := json.MarshalIndent(a, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(filePath, data, 0644)
 }
 
-func (a *Achievements) GetAll() []Achievement {
-	return []Achievement{
-		{
-			ID:          "first_blood",
-			Name:        "First Blood",
-			Description: "Win your first game",
-			Unlocked:    a.FirstBlood,
-		},
-		{
-			ID:          "perfect_game",
-			Name:        "Perfect Game",
-			Description: "Win without losing any ships",
-			Unlocked:    a.PerfectGame,
-		},
-		{
-			ID:          "domination",
-			Name:        "Domination",
-			Description: "Win with all ships at full health",
-			Unlocked:    a.Domination,
-		},
-		{
-			ID:          "sharpshooter",
-			Name:        "Sharpshooter",
-			Description: "Win with 90%+ accuracy",
-			Unlocked:    a.Sharpshooter,
-		},
-		{
-			ID:          "comeback_king",
-			Name:        "Comeback King",
-			Description: "Win after losing 4 ships",
-			Unlocked:    a.ComebackKing,
-		},
-		{
-			ID:          "hardcore_victor",
-			Name:        "Hardcore Victor",
-			Description: "Beat Hard difficulty",
-			Unlocked:    a.HardcoreVictor,
-		},
-		{
-			ID:          "salvo_master",
-			Name:        "Salvo Master",
-			Description: "Win in Salvo mode",
-			Unlocked:    a.SalvoMaster,
-		},
-		{
-			ID:          "efficient",
-			Name:        "Efficient",
-			Description: "Win in under 50 shots",
-			Unlocked:    a.Efficient,
-		},
-		{
-			ID:          "small_board_win",
-			Name:        "Compact Commander",
-			Description: "Win on 8x8 board",
-			Unlocked:    a.SmallBoardWin,
-		},
-		{
-			ID:          "large_board_win",
-			Name:        "Admiral of the Seas",
-			Description: "Win on 12x12 board",
-			Unlocked:    a.LargeBoardWin,
-		},
-	}
+// GameSummary is what achievement rules know about a finished game, from the
+// player's point of view
+type GameSummary struct {
+	Won          bool
+	Difficulty   string
+	Rows         int
+	Cols         int
+	Salvo        bool
+	FleetSize    int     // Ships in each fleet
+	ShotsFired   int     // Shots the player fired
+	Accuracy     float64 // Fraction of the player's shots that hit
+	ShipsLost    int     // Player ships sunk
+	ShipsDamaged int     // Player ships hit at least once, sunk or not
+	CleanSinks   int     // Enemy ships sunk without a miss after the first hit
 }
 
-func (a *Achievements) CheckAndUnlock(g *game.Game) []Achievement {
-	newlyUnlocked := []Achievement{}
-
-	if g.Winner != "Player" {
-		return newlyUnlocked
+// Summarize collects the summary of a game against Captain Claude
+func Summarize(g *game.Game) GameSummary {
+	s := GameSummary{
+		Won:        g.Winner == "Player",
+		Difficulty: g.ComputerStrategy,
+		Rows:       g.Rows,
+		Cols:       g.Cols,
+		Salvo:      g.SalvoMode,
+		FleetSize:  len(g.Fleet.Ships),
+		ShotsFired: g.Shots.ShotsFired(game.PlayerSide),
+		Accuracy:   g.Shots.Accuracy(game.PlayerSide),
 	}
 
-	// First Blood - first win
-	if !a.FirstBlood {
-		a.FirstBlood = true
-		newlyUnlocked = append(newlyUnlocked, Achievement{
-			ID:          "first_blood",
-			Name:        "First Blood",
-			Description: "Win your first game",
-			Unlocked:    true,
-		})
-	}
-
-	// Perfect Game - no ships sunk
-	sunkShips := 0
 	for _, ship := range g.PlayerBoard.Ships {
 		if ship.IsSunk() {
-			sunkShips++
+			s.ShipsLost++
 		}
-	}
-
-	if sunkShips == 0 && !a.PerfectGame {
-		a.PerfectGame = true
-		newlyUnlocked = append(newlyUnlocked, Achievement{
-			ID:          "perfect_game",
-			Name:        "Perfect Game",
-			Description: "Win without losing any ships",
-			Unlocked:    true,
-		})
-	}
-
-	// Domination - all ships at full health
-	anyDamage := false
-	for _, ship := range g.PlayerBoard.Ships {
 		for _, hit := range ship.Hits {
 			if hit {
-				anyDamage = true
+				s.ShipsDamaged++
 				break
 			}
 		}
-		if anyDamage {
-			break
-		}
 	}
 
-	if !anyDamage && !a.Domination {
-		a.Domination = true
-		newlyUnlocked = append(newlyUnlocked, Achievement{
-			ID:          "domination",
-			Name:        "Domination",
-			Description: "Win with all ships at full health",
-			Unlocked:    true,
-		})
+	for _, ship := range g.ComputerBoard.Ships {
+		if g.Shots.HitsBeforeSink(game.PlayerSide, ship.Name) == ship.Length {
+			s.CleanSinks++
+		}
 	}
+	return s
+}
 
-	// Comeback King - win after losing 4 ships
-	if sunkShips >= 4 && !a.ComebackKing {
-		a.ComebackKing = true
-		newlyUnlocked = append(newlyUnlocked, Achievement{
-			ID:          "comeback_king",
-			Name:        "Comeback King",
-			Description: "Win after losing 4 ships",
-			Unlocked:    true,
-		})
-	}
+// AchievementRule defines an achievement and when it is earned
+type AchievementRule struct {
+	ID          string
+	Name        string
+	Description string
+	Earned      func(s GameSummary) bool
+}
 
-	// Hardcore Victor - beat Hard difficulty
-	if g.Difficulty == game.Hard && !a.HardcoreVictor {
-		a.HardcoreVictor = true
-		newlyUnlocked = append(newlyUnlocked, Achievement{
-			ID:          "hardcore_victor",
-			Name:        "Hardcore Victor",
-			Description: "Beat Hard difficulty",
-			Unlocked:    true,
-		})
+// achievementRules are all achievements, in display order. IDs are saved in
+// the profile and must not change.
+var achievementRules = []AchievementRule{
+	{
+		ID:          "first_blood",
+		Name:        "First Blood",
+		Description: "Win your first game",
+		Earned:      func(s GameSummary) bool { return s.Won },
+	},
+	{
+		ID:          "perfect_game",
+		Name:        "Perfect Game",
+		Description: "Win without losing any ships",
+		Earned:      func(s GameSummary) bool { return s.Won && s.ShipsLost == 0 },
+	},
+	{
+		ID:          "domination",
+		Name:        "Domination",
+		Description: "Win with all ships at full health",
+		Earned:      func(s GameSummary) bool { return s.Won && s.ShipsDamaged == 0 },
+	},
+	{
+		ID:          "sharpshooter",
+		Name:        "Sharpshooter",
+		Description: "Win with 90%+ accuracy",
+		Earned:      func(s GameSummary) bool { return s.Won && s.Accuracy >= 0.9 },
+	},
+	{
+		ID:          "comeback_king",
+		Name:        "Comeback King",
+		Description: "Win after losing all but one ship",
+		Earned:      func(s GameSummary) bool { return s.Won && s.FleetSize > 1 && s.ShipsLost == s.FleetSize-1 },
+	},
+	{
+		ID:          "hardcore_victor",
+		Name:        "Hardcore Victor",
+		Description: "Beat Hard difficulty",
+		Earned: func(s GameSummary) bool {
+			return s.Won && (s.Difficulty == game.Hard.String() || s.Difficulty == game.Expert.String())
+		},
+	},
+	{
+		ID:          "salvo_master",
+		Name:        "Salvo Master",
+		Description: "Win in Salvo mode",
+		Earned:      func(s GameSummary) bool { return s.Won && s.Salvo },
+	},
+	{
+		ID:          "efficient",
+		Name:        "Efficient",
+		Description: "Win in under 50 shots",
+		Earned:      func(s GameSummary) bool { return s.Won && s.ShotsFired < 50 },
+	},
+	{
+		ID:          "lucky_shot",
+		Name:        "Lucky Shot",
+		Description: "Sink a ship without missing after the first hit",
+		Earned:      func(s GameSummary) bool { return s.CleanSinks > 0 },
+	},
+	{
+		ID:          "small_board_win",
+		Name:        "Compact Commander",
+		Description: "Win on 8x8 board",
+		Earned:      func(s GameSummary) bool { return s.Won && s.Rows == 8 && s.Cols == 8 },
+	},
+	{
+		ID:          "large_board_win",
+		Name:        "Admiral of the Seas",
+		Description: "Win on 12x12 board",
+		Earned:      func(s GameSummary) bool { return s.Won && s.Rows == 12 && s.Cols == 12 },
+	},
+}
+
+// Achievements are the unlocked achievements, keyed by ID
+type Achievements map[string]*Unlock
+
+// migrateAchievements reads achievements saved as plain flags, before unlocks
+// were recorded with their date and game settings
+func migrateAchievements(data []byte) (Achievements, error) {
+	var flags map[string]bool
+	if err := json.Unmarshal(data, &flags); err != nil {
+		return nil, err
 	}
 
-	// Salvo Master - win in Salvo mode
-	if g.SalvoMode && !a.SalvoMaster {
-		a.SalvoMaster = true
-		newlyUnlocked = append(newlyUnlocked, Achievement{
-			ID:          "salvo_master",
-			Name:        "Salvo Master",
-			Description: "Win in Salvo mode",
-			Unlocked:    true,
-		})
+	a := Achievements{}
+	for id, unlocked := range flags {
+		if unlocked {
+			a[id] = &Unlock{}
+		}
 	}
+	return a, nil
+}
 
-	// Board size achievements
-	if g.BoardSize == 8 && !a.SmallBoardWin {
-		a.SmallBoardWin = true
-		newlyUnlocked = append(newlyUnlocked, Achievement{
-			ID:          "small_board_win",
-			Name:        "Compact Commander",
-			Description: "Win on 8x8 board",
-			Unlocked:    true,
-		})
+// GetAll returns every achievement, locked or not, in display order
+func (a Achievements) GetAll() []Achievement {
+	all := make([]Achievement, len(achievementRules))
+	for i, rule := range achievementRules {
+		all[i] = rule.achievement(a[rule.ID])
 	}
+	return all
+}
 
-	if g.BoardSize == 12 && !a.LargeBoardWin {
-		a.LargeBoardWin = true
-		newlyUnlocked = append(newlyUnlocked, Achievement{
-			ID:          "large_board_win",
-			Name:        "Admiral of the Seas",
-			Description: "Win on 12x12 board",
-			Unlocked:    true,
-		})
+// achievement describes the rule's achievement with the given unlock
+func (r AchievementRule) achievement(unlock *Unlock) Achievement {
+	return Achievement{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Unlock:      unlock,
 	}
+}
+
+// CheckAndUnlock unlocks the achievements earned by the finished game g at
+// the given time and returns them
+func (a Achievements) CheckAndUnlock(g *game.Game, at time.Time) []Achievement {
+	newlyUnlocked := []Achievement{}
 
-	// Save if any new achievements
-	if len(newlyUnlocked) > 0 {
-		a.Save()
+	// Achievements are only earned against Captain Claude
+	if g.Mode != game.VsComputer {
+		return newlyUnlocked
 	}
 
+	summary := Summarize(g)
+	unlock := newUnlock(g, at)
+	for _, rule := range achievementRules {
+		if a[rule.ID] == nil && rule.Earned(summary) {
+			a[rule.ID] = unlock
+			newlyUnlocked = append(newlyUnlocked, rule.achievement(unlock))
+		}
+	}
 	return newlyUnlocked
 }
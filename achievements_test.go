@@ -0,0 +1,132 @@
+package main
+
+import (
+	"battleship/game"
+	"testing"
+)
+
+// summary returns an unremarkable won game on a 10x10 board, changed by edit
+func summary(edit func(s *GameSummary)) GameSummary {
+	s := GameSummary{
+		Won:          true,
+		Difficulty:   game.Normal.String(),
+		Rows:         10,
+		Cols:         10,
+		FleetSize:    5,
+		ShotsFired:   60,
+		Accuracy:     0.5,
+		ShipsLost:    2,
+		ShipsDamaged: 3,
+	}
+	if edit != nil {
+		edit(&s)
+	}
+	return s
+}
+
+// lost marks a summary as a lost game
+func lost(s *GameSummary) {
+	s.Won = false
+}
+
+// achievementCase is a game summary and whether a rule should be earned by it
+type achievementCase struct {
+	name    string
+	summary GameSummary
+	want    bool
+}
+
+// achievementCases holds a table of cases for every achievement rule, by ID
+var achievementCases = map[string][]achievementCase{
+	"first_blood": {
+		{"win", summary(nil), true},
+		{"loss", summary(lost), false},
+	},
+	"perfect_game": {
+		{"no ships lost", summary(func(s *GameSummary) { s.ShipsLost = 0 }), true},
+		{"one ship lost", summary(func(s *GameSummary) { s.ShipsLost = 1 }), false},
+		{"loss without losing ships", summary(func(s *GameSummary) { lost(s); s.ShipsLost = 0 }), false},
+	},
+	"domination": {
+		{"no ships damaged", summary(func(s *GameSummary) { s.ShipsLost, s.ShipsDamaged = 0, 0 }), true},
+		{"one ship damaged", summary(func(s *GameSummary) { s.ShipsLost, s.ShipsDamaged = 0, 1 }), false},
+		{"loss", summary(func(s *GameSummary) { lost(s); s.ShipsDamaged = 0 }), false},
+	},
+	"sharpshooter": {
+		{"exactly 90%", summary(func(s *GameSummary) { s.Accuracy = 0.9 }), true},
+		{"perfect accuracy", summary(func(s *GameSummary) { s.Accuracy = 1 }), true},
+		{"just under 90%", summary(func(s *GameSummary) { s.Accuracy = 0.89 }), false},
+		{"loss at 90%", summary(func(s *GameSummary) { lost(s); s.Accuracy = 0.9 }), false},
+	},
+	"comeback_king": {
+		{"one ship left", summary(func(s *GameSummary) { s.ShipsLost = 4 }), true},
+		{"two ships left", summary(func(s *GameSummary) { s.ShipsLost = 3 }), false},
+		{"fleet of one", summary(func(s *GameSummary) { s.FleetSize, s.ShipsLost = 1, 0 }), false},
+		{"fleet of two, one lost", summary(func(s *GameSummary) { s.FleetSize, s.ShipsLost = 2, 1 }), true},
+		{"loss", summary(func(s *GameSummary) { lost(s); s.ShipsLost = 4 }), false},
+	},
+	"hardcore_victor": {
+		{"hard", summary(func(s *GameSummary) { s.Difficulty = game.Hard.String() }), true},
+		{"expert", summary(func(s *GameSummary) { s.Difficulty = game.Expert.String() }), true},
+		{"normal", summary(nil), false},
+		{"easy", summary(func(s *GameSummary) { s.Difficulty = game.Easy.String() }), false},
+		{"loss on hard", summary(func(s *GameSummary) { lost(s); s.Difficulty = game.Hard.String() }), false},
+	},
+	"salvo_master": {
+		{"salvo", summary(func(s *GameSummary) { s.Salvo = true }), true},
+		{"single shot", summary(nil), false},
+		{"loss in salvo", summary(func(s *GameSummary) { lost(s); s.Salvo = true }), false},
+	},
+	"efficient": {
+		{"49 shots", summary(func(s *GameSummary) { s.ShotsFired = 49 }), true},
+		{"50 shots", summary(func(s *GameSummary) { s.ShotsFired = 50 }), false},
+		{"loss in 40 shots", summary(func(s *GameSummary) { lost(s); s.ShotsFired = 40 }), false},
+	},
+	"lucky_shot": {
+		{"one clean sink", summary(func(s *GameSummary) { s.CleanSinks = 1 }), true},
+		{"no clean sinks", summary(nil), false},
+		{"clean sink in a loss", summary(func(s *GameSummary) { lost(s); s.CleanSinks = 1 }), true},
+	},
+	"small_board_win": {
+		{"8x8", summary(func(s *GameSummary) { s.Rows, s.Cols = 8, 8 }), true},
+		{"8x12", summary(func(s *GameSummary) { s.Rows, s.Cols = 8, 12 }), false},
+		{"10x10", summary(nil), false},
+		{"loss on 8x8", summary(func(s *GameSummary) { lost(s); s.Rows, s.Cols = 8, 8 }), false},
+	},
+	"large_board_win": {
+		{"12x12", summary(func(s *GameSummary) { s.Rows, s.Cols = 12, 12 }), true},
+		{"8x12", summary(func(s *GameSummary) { s.Rows, s.Cols = 8, 12 }), false},
+		{"12x8", summary(func(s *GameSummary) { s.Rows, s.Cols = 12, 8 }), false},
+		{"loss on 12x12", summary(func(s *GameSummary) { lost(s); s.Rows, s.Cols = 12, 12 }), false},
+	},
+}
+
+func TestAchievementRules(t *testing.T) {
+	for _, rule := range achievementRules {
+		cases, ok := achievementCases[rule.ID]
+		if !ok {
+			t.Errorf("no test cases for %s", rule.ID)
+			continue
+		}
+		for _, tc := range cases {
+			if got := rule.Earned(tc.summary); got != tc.want {
+				t.Errorf("%s, %s: Earned() = %v, want %v", rule.ID, tc.name, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestAchievementCasesMatchRules(t *testing.T) {
+	ids := map[string]bool{}
+	for _, rule := range achievementRules {
+		if ids[rule.ID] {
+			t.Errorf("duplicate achievement ID %s", rule.ID)
+		}
+		ids[rule.ID] = true
+	}
+	for id := range achievementCases {
+		if !ids[id] {
+			t.Errorf("test cases for unknown achievement %s", id)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"battleship/game"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// resolveFleet returns the built-in fleet whose name starts with name,
+// ignoring case, or otherwise loads a fleet from the file at that path. A
+// fleet file may not reuse the name of a different built-in fleet, as the
+// menu tells fleets apart by name.
+func resolveFleet(name string) (game.Fleet, error) {
+	for _, fleet := range game.FleetPresets() {
+		if strings.HasPrefix(strings.ToLower(fleet.Name), strings.ToLower(name)) {
+			return fleet, nil
+		}
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return game.Fleet{}, fmt.Errorf("%q is neither a built-in fleet nor a readable fleet file: %w", name, err)
+	}
+	defer file.Close()
+
+	custom, err := game.LoadFleet(file)
+	if err != nil {
+		return game.Fleet{}, err
+	}
+	for _, fleet := range game.FleetPresets() {
+		if strings.EqualFold(fleet.Name, custom.Name) && !reflect.DeepEqual(fleet, custom) {
+			return game.Fleet{}, fmt.Errorf("fleet file %q is named %q like a built-in fleet, give it another name", name, custom.Name)
+		}
+	}
+	return custom, nil
+}
+
+// menuFleets returns the fleets offered on the main menu: the built-in fleets,
+// followed by the fleet given on the command line if it is not one of them
+func menuFleets(custom *game.Fleet) []game.Fleet {
+	fleets := game.FleetPresets()
+	if custom == nil {
+		return fleets
+	}
+	for _, fleet := range fleets {
+		if fleet.Name == custom.Name {
+			return fleets
+		}
+	}
+	return append(fleets, *custom)
+}
+
+// cycleFleet returns the fleet step places away from current
+func cycleFleet(fleets []game.Fleet, current game.Fleet, step int) game.Fleet {
+	for i, fleet := range fleets {
+		if fleet.Name == current.Name {
+			return fleets[(i+step+len(fleets))%len(fleets)]
+		}
+	}
+	return fleets[0]
+}
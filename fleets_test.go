@@ -0,0 +1,47 @@
+package main
+
+import (
+	"battleship/game"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fleetFile writes a fleet to a JSON file and returns its path
+func fleetFile(t *testing.T, fleet game.Fleet) string {
+	t.Helper()
+	data, err := json.Marshal(fleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "fleet.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveFleetRejectsPresetNames(t *testing.T) {
+	impostor := game.Fleet{Name: "classic hasbro", Ships: []game.ShipSpec{{Name: "Raft", Length: 1}}}
+	if _, err := resolveFleet(fleetFile(t, impostor)); err == nil {
+		t.Error("resolveFleet() loaded a fleet named like a built-in fleet")
+	}
+
+	// A copy of a built-in fleet is that fleet, and shows on the menu once
+	fleet, err := resolveFleet(fleetFile(t, game.ClassicFleet))
+	if err != nil {
+		t.Fatalf("resolveFleet() = %v", err)
+	}
+	if got, want := len(menuFleets(&fleet)), len(game.FleetPresets()); got != want {
+		t.Errorf("menuFleets() has %d fleets, want %d", got, want)
+	}
+
+	armada := game.Fleet{Name: "Armada", Ships: []game.ShipSpec{{Name: "Dreadnought", Length: 6}}}
+	if fleet, err = resolveFleet(fleetFile(t, armada)); err != nil {
+		t.Fatalf("resolveFleet() = %v", err)
+	}
+	if fleets := menuFleets(&fleet); fleets[len(fleets)-1].Name != "Armada" {
+		t.Errorf("menuFleets() = %v, want Armada last", fleets)
+	}
+}
@@ -0,0 +1,186 @@
+package game
+
+import (
+	"math/rand"
+	"sort"
+)
+
+func init() {
+	RegisterStrategy(Easy.String(), easyStrategy{})
+	RegisterStrategy(Normal.String(), normalStrategy{})
+	RegisterStrategy(Hard.String(), hardStrategy{})
+	RegisterStrategy(Expert.String(), expertStrategy{})
+}
+
+// easyStrategy implements easy difficulty - random attacks
+type easyStrategy struct{}
+
+// NextTarget picks a random untried cell
+func (easyStrategy) NextTarget(view FogView, rng *rand.Rand) Position {
+	var pos Position
+	found := false
+
+	for !found {
+		row := rng.Intn(view.Rows())
+		col := rng.Intn(view.Cols())
+		pos = Position{Row: row, Col: col}
+
+		if view.IsTargetable(pos) {
+			found = true
+		}
+	}
+
+	return pos
+}
+
+// NextSalvo picks each shot of a salvo in turn
+func (s easyStrategy) NextSalvo(view FogView, shots int, rng *rand.Rand) []Position {
+	return SequentialSalvo(s, view, shots, rng)
+}
+
+// normalStrategy implements normal difficulty - hunts around hits
+type normalStrategy struct{}
+
+// NextTarget fires next to a known hit, or randomly if there are none
+func (normalStrategy) NextTarget(view FogView, rng *rand.Rand) Position {
+	// First, look for existing hits to follow up on
+	for _, hit := range likelyHits(view) {
+		// Found a hit, try adjacent cells
+		adjacents := []Position{
+			{Row: hit.Row - 1, Col: hit.Col},
+			{Row: hit.Row + 1, Col: hit.Col},
+			{Row: hit.Row, Col: hit.Col - 1},
+			{Row: hit.Row, Col: hit.Col + 1},
+		}
+
+		// Shuffle adjacents for variety
+		for i := range adjacents {
+			j := rng.Intn(i + 1)
+			adjacents[i], adjacents[j] = adjacents[j], adjacents[i]
+		}
+
+		for _, adj := range adjacents {
+			if view.IsTargetable(adj) {
+				return adj
+			}
+		}
+	}
+
+	// No hits to follow up on, attack randomly
+	return easyStrategy{}.NextTarget(view, rng)
+}
+
+// likelyHits returns the cells worth following up on: known hits in board
+// order, then cells of unresolved salvos, those from the salvos with the
+// largest share of hits first
+func likelyHits(view FogView) []Position {
+	var hits, uncertain []Position
+	for row := 0; row < view.Rows(); row++ {
+		for col := 0; col < view.Cols(); col++ {
+			pos := Position{Row: row, Col: col}
+			switch view.Cell(pos) {
+			case FogHit:
+				hits = append(hits, pos)
+			case FogUncertain:
+				uncertain = append(uncertain, pos)
+			}
+		}
+	}
+
+	sort.SliceStable(uncertain, func(i, j int) bool {
+		return view.HitChance(uncertain[i]) > view.HitChance(uncertain[j])
+	})
+	return append(hits, uncertain...)
+}
+
+// NextSalvo picks each shot of a salvo in turn
+func (s normalStrategy) NextSalvo(view FogView, shots int, rng *rand.Rand) []Position {
+	return SequentialSalvo(s, view, shots, rng)
+}
+
+// hardStrategy implements hard difficulty - smart pattern hunting and direction following
+type hardStrategy struct{}
+
+// NextTarget extends lines of hits, then hunts on a checkerboard
+func (hardStrategy) NextTarget(view FogView, rng *rand.Rand) Position {
+	// Look for hits in a line (ship orientation detected)
+	for row := 0; row < view.Rows(); row++ {
+		for col := 0; col < view.Cols(); col++ {
+			if view.Cell(Position{Row: row, Col: col}) == FogHit {
+				// Check horizontal line
+				if col+1 < view.Cols() && view.Cell(Position{Row: row, Col: col + 1}) == FogHit {
+					// Found horizontal ship, extend in both directions
+					// Try right first
+					if col+2 < view.Cols() {
+						adj := Position{Row: row, Col: col + 2}
+						if view.IsTargetable(adj) {
+							return adj
+						}
+					}
+					// Try left
+					if col-1 >= 0 {
+						adj := Position{Row: row, Col: col - 1}
+						if view.IsTargetable(adj) {
+							return adj
+						}
+					}
+				}
+
+				// Check vertical line
+				if row+1 < view.Rows() && view.Cell(Position{Row: row + 1, Col: col}) == FogHit {
+					// Found vertical ship, extend in both directions
+					// Try down first
+					if row+2 < view.Rows() {
+						adj := Position{Row: row + 2, Col: col}
+						if view.IsTargetable(adj) {
+							return adj
+						}
+					}
+					// Try up
+					if row-1 >= 0 {
+						adj := Position{Row: row - 1, Col: col}
+						if view.IsTargetable(adj) {
+							return adj
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// No line detected, use normal mode's adjacent hunting
+	for _, hit := range likelyHits(view) {
+		adjacents := []Position{
+			{Row: hit.Row - 1, Col: hit.Col},
+			{Row: hit.Row + 1, Col: hit.Col},
+			{Row: hit.Row, Col: hit.Col - 1},
+			{Row: hit.Row, Col: hit.Col + 1},
+		}
+
+		for _, adj := range adjacents {
+			if view.IsTargetable(adj) {
+				return adj
+			}
+		}
+	}
+
+	// No hits to follow, use checkerboard pattern for efficient hunting
+	for row := 0; row < view.Rows(); row++ {
+		for col := 0; col < view.Cols(); col++ {
+			if (row+col)%2 == 0 { // Checkerboard pattern
+				pos := Position{Row: row, Col: col}
+				if view.IsTargetable(pos) {
+					return pos
+				}
+			}
+		}
+	}
+
+	// Checkerboard exhausted, fill in remaining cells
+	return easyStrategy{}.NextTarget(view, rng)
+}
+
+// NextSalvo picks each shot of a salvo in turn
+func (s hardStrategy) NextSalvo(view FogView, shots int, rng *rand.Rand) []Position {
+	return SequentialSalvo(s, view, shots, rng)
+}
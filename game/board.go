@@ -1,5 +1,13 @@
 package game
 
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
 // CellState represents the state of a cell on the board
 type CellState int
 
@@ -10,22 +18,32 @@ const (
 	Hit
 )
 
+// ErrShipHit is returned when moving a ship that has already been hit
+var ErrShipHit = errors.New("a ship that has been hit cannot be moved")
+
+// MaxBoardDimension is the largest number of rows or columns a board can
+// have, so that every column has a letter from A to Z
+const MaxBoardDimension = 26
+
 // Board represents a game board
 type Board struct {
-	Size  int
+	Rows  int
+	Cols  int
 	Grid  [][]CellState
 	Ships []*Ship
+	Rules PlacementRules
 }
 
-// NewBoard creates a new board of the given size
-func NewBoard(size int) *Board {
-	grid := make([][]CellState, size)
+// NewBoard creates a new board with the given number of rows and columns
+func NewBoard(rows, cols int) *Board {
+	grid := make([][]CellState, rows)
 	for i := range grid {
-		grid[i] = make([]CellState, size)
+		grid[i] = make([]CellState, cols)
 	}
 
 	return &Board{
-		Size:  size,
+		Rows:  rows,
+		Cols:  cols,
 		Grid:  grid,
 		Ships: make([]*Ship, 0),
 	}
@@ -33,7 +51,7 @@ func NewBoard(size int) *Board {
 
 // IsValidPosition checks if a position is within board bounds
 func (b *Board) IsValidPosition(pos Position) bool {
-	return pos.Row >= 0 && pos.Row < b.Size && pos.Col >= 0 && pos.Col < b.Size
+	return pos.Row >= 0 && pos.Row < b.Rows && pos.Col >= 0 && pos.Col < b.Cols
 }
 
 // CanPlaceShip checks if a ship can be placed at the given position
@@ -44,7 +62,7 @@ func (b *Board) CanPlaceShip(pos Position, length int, orientation Orientation)
 		if !b.IsValidPosition(p) {
 			return false
 		}
-		if b.Grid[p.Row][p.Col] == ShipCell {
+		if b.Grid[p.Row][p.Col] == ShipCell || b.IsBlocked(p) {
 			return false
 		}
 	}
@@ -52,6 +70,20 @@ func (b *Board) CanPlaceShip(pos Position, length int, orientation Orientation)
 	return true
 }
 
+// IsBlocked returns true if the placement rules keep ships off an empty cell,
+// because it is next to a ship that has already been placed
+func (b *Board) IsBlocked(pos Position) bool {
+	if !b.Rules.NoTouch || b.GetCell(pos) != Empty {
+		return false
+	}
+	for _, p := range neighbours(pos, b.Rows, b.Cols) {
+		if cell := b.Grid[p.Row][p.Col]; cell == ShipCell || cell == Hit {
+			return true
+		}
+	}
+	return false
+}
+
 // PlaceShip places a ship on the board
 func (b *Board) PlaceShip(ship *Ship, pos Position, orientation Orientation) bool {
 	if !b.CanPlaceShip(pos, ship.Length, orientation) {
@@ -69,6 +101,56 @@ func (b *Board) PlaceShip(ship *Ship, pos Position, orientation Orientation) boo
 	return true
 }
 
+// ShipAt returns the ship occupying pos, or nil if there is none
+func (b *Board) ShipAt(pos Position) *Ship {
+	for _, ship := range b.Ships {
+		if ship.covers(pos) {
+			return ship
+		}
+	}
+	return nil
+}
+
+// CanMoveShip checks if a placed ship could be moved to the given position,
+// ignoring the cells it occupies now
+func (b *Board) CanMoveShip(ship *Ship, pos Position, orientation Orientation) bool {
+	b.liftShip(ship)
+	defer b.lowerShip(ship)
+	return b.CanPlaceShip(pos, ship.Length, orientation)
+}
+
+// MoveShip moves or rotates a placed, unhit ship, keeping its place in Ships.
+// It returns ErrShipHit if any cell of the ship has been hit.
+func (b *Board) MoveShip(ship *Ship, pos Position, orientation Orientation) error {
+	for _, hit := range ship.Hits {
+		if hit {
+			return ErrShipHit
+		}
+	}
+	if !b.CanMoveShip(ship, pos, orientation) {
+		return fmt.Errorf("%s does not fit at %s", ship.Name, pos)
+	}
+
+	b.liftShip(ship)
+	ship.Positions = b.getShipPositions(pos, ship.Length, orientation)
+	b.lowerShip(ship)
+	return nil
+}
+
+// liftShip clears the cells of a ship without removing it from Ships
+func (b *Board) liftShip(ship *Ship) {
+	for _, p := range ship.Positions {
+		b.Grid[p.Row][p.Col] = Empty
+	}
+}
+
+// lowerShip marks the cells of a ship as occupied
+func (b *Board) lowerShip(ship *Ship) {
+	for _, p := range ship.Positions {
+		b.Grid[p.Row][p.Col] = ShipCell
+	}
+}
+
 // getShipPositions returns all positions a ship would occupy
 func (b *Board) getShipPositions(pos Position, length int, orientation Orientation) []Position {
 	positions := make([]Position, length)
@@ -128,3 +210,52 @@ func (b *Board) GetCell(pos Position) CellState {
 	}
 	return b.Grid[pos.Row][pos.Col]
 }
+
+// CheckBoardSize returns an error if a board of the given dimensions is out of
+// range or too small to hold the fleet under the placement rules
+func CheckBoardSize(rows, cols int, fleet Fleet, rules PlacementRules) error {
+	if rows < 1 || cols < 1 || rows > MaxBoardDimension || cols > MaxBoardDimension {
+		return fmt.Errorf("board must be between 1x1 and %dx%d, got %dx%d", MaxBoardDimension, MaxBoardDimension, rows, cols)
+	}
+
+	cells := 0
+	for _, spec := range fleet.Ships {
+		if spec.Length > rows && spec.Length > cols {
+			return fmt.Errorf("the %s (length %d) does not fit on a %dx%d board", spec.Name, spec.Length, rows, cols)
+		}
+		cells += spec.Length
+	}
+	if cells > rows*cols {
+		return fmt.Errorf("the %s fleet needs %d cells, but a %dx%d board only has %d", fleet.Name, cells, rows, cols, rows*cols)
+	}
+
+	// Counting cells cannot tell whether the ships can be arranged, least of
+	// all when they may not touch, so look for a layout
+	err := RandomFleet(NewBoard(rows, cols), fleet, rules, rand.New(rand.NewSource(1)))
+	if errors.Is(err, ErrLayoutSearchGaveUp) {
+		return fmt.Errorf("no layout was found for the %s fleet on a %dx%d board, try a larger board or fewer ships", fleet.Name, rows, cols)
+	}
+	if err != nil {
+		if rules.NoTouch {
+			return fmt.Errorf("the %s fleet does not fit on a %dx%d board without ships touching", fleet.Name, rows, cols)
+		}
+		return fmt.Errorf("the %s fleet does not fit on a %dx%d board", fleet.Name, rows, cols)
+	}
+	return nil
+}
+
+// ParseBoardSize parses board dimensions written as rows x columns, e.g.
+// "8x12", or as a single number for a square board
+func ParseBoardSize(s string) (rows, cols int, err error) {
+	rowText, colText, found := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "x")
+	if !found {
+		colText = rowText
+	}
+
+	rows, rowErr := strconv.Atoi(strings.TrimSpace(rowText))
+	cols, colErr := strconv.Atoi(strings.TrimSpace(colText))
+	if rowErr != nil || colErr != nil {
+		return 0, 0, fmt.Errorf("invalid board size %q, expected e.g. 10 or 8x12", s)
+	}
+	return rows, cols, nil
+}
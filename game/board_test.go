@@ -0,0 +1,32 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMoveShip(t *testing.T) {
+	b := NewBoard(10, 10)
+	destroyer := NewShip(ShipSpec{Name: "Destroyer", Length: 2})
+	cruiser := NewShip(ShipSpec{Name: "Cruiser", Length: 3})
+	b.PlaceShip(destroyer, Position{Row: 0, Col: 0}, Horizontal)
+	b.PlaceShip(cruiser, Position{Row: 5, Col: 5}, Vertical)
+
+	if err := b.MoveShip(destroyer, Position{Row: 2, Col: 3}, Vertical); err != nil {
+		t.Fatalf("MoveShip() = %v", err)
+	}
+	if b.GetCell(Position{Row: 0, Col: 0}) != Empty || b.ShipAt(Position{Row: 3, Col: 3}) != destroyer {
+		t.Error("the destroyer did not move")
+	}
+	if err := b.MoveShip(destroyer, Position{Row: 6, Col: 4}, Horizontal); err == nil {
+		t.Error("MoveShip() onto the cruiser succeeded")
+	}
+
+	b.Attack(Position{Row: 6, Col: 5})
+	if err := b.MoveShip(cruiser, Position{Row: 0, Col: 0}, Horizontal); !errors.Is(err, ErrShipHit) {
+		t.Errorf("MoveShip() of a hit ship = %v, want ErrShipHit", err)
+	}
+	if b.GetCell(Position{Row: 6, Col: 5}) != Hit || b.GetCell(Position{Row: 0, Col: 0}) != Empty {
+		t.Error("the hit cruiser was moved")
+	}
+}
@@ -0,0 +1,196 @@
+package game
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrCommitmentMismatch is returned when a revealed fleet does not hash to the
+// commitment published at the start of the game
+var ErrCommitmentMismatch = errors.New("revealed fleet does not match its commitment")
+
+// FleetShip is a ship's position in a revealed fleet layout
+type FleetShip struct {
+	Name      string     `json:"name"`
+	Positions []Position `json:"positions"`
+}
+
+// FleetReveal is a fleet layout and the nonce that was hashed with it
+type FleetReveal struct {
+	Ships []FleetShip `json:"ships"`
+	Nonce string      `json:"nonce"`
+}
+
+// FleetCommitment is a hash that binds a player to a fleet layout without
+// revealing it. The hash is published when the game starts, and Reveal is
+// kept secret until the game is over.
+type FleetCommitment struct {
+	Hash   string
+	Reveal FleetReveal
+}
+
+// CommitFleet commits to the layout of the ships on a board using a random nonce
+func CommitFleet(board *Board) (FleetCommitment, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return FleetCommitment{}, err
+	}
+
+	reveal := FleetReveal{Nonce: hex.EncodeToString(nonce)}
+	for _, ship := range board.Ships {
+		reveal.Ships = append(reveal.Ships, FleetShip{Name: ship.Name, Positions: ship.Positions})
+	}
+
+	hash, err := reveal.Hash()
+	if err != nil {
+		return FleetCommitment{}, err
+	}
+	return FleetCommitment{Hash: hash, Reveal: reveal}, nil
+}
+
+// Hash returns the hex-encoded SHA-256 of the serialized layout followed by
+// the nonce
+func (r FleetReveal) Hash() (string, error) {
+	layout, err := json.Marshal(r.Ships)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write(layout)
+	h.Write([]byte(r.Nonce))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Discrepancy is a shot whose reported result does not match the revealed fleet
+type Discrepancy struct {
+	Shot     ShotRecord // The shot as it was reported during the game
+	Result   ShotResult // What the revealed fleet says the result was
+	ShipName string     // Ship hit according to the revealed fleet
+}
+
+// String describes the discrepancy
+func (d Discrepancy) String() string {
+	return fmt.Sprintf("turn %d: %s was reported as %s, but was %s",
+		d.Shot.Turn, d.Shot.Target, describeResult(d.Shot.Result, d.Shot.Ship), describeResult(d.Result, d.ShipName))
+}
+
+// describeResult formats a shot result for a discrepancy
+func describeResult(result ShotResult, ship string) string {
+	switch result {
+	case ShotHit:
+		return "a hit on the " + ship
+	case ShotSunk:
+		return "sinking the " + ship
+	}
+	return "a miss"
+}
+
+// VerifyFleet checks a revealed fleet against its commitment, the fleet each
+// player was meant to place and the placement rules, then replays every reported shot at it and
+// returns the shots whose reported result does not match. An error means the
+// reveal itself cannot be trusted.
+func VerifyFleet(commitment string, reveal FleetReveal, rows, cols int, fleet Fleet, rules PlacementRules, shots []ShotRecord) ([]Discrepancy, error) {
+	hash, err := reveal.Hash()
+	if err != nil {
+		return nil, err
+	}
+	if hash != commitment {
+		return nil, ErrCommitmentMismatch
+	}
+
+	board, err := revealedBoard(reveal, rows, cols, fleet, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	var discrepancies []Discrepancy
+	for _, shot := range shots {
+		if !board.IsValidPosition(shot.Target) {
+			return nil, fmt.Errorf("reported shot at %s is off the board", shot.Target)
+		}
+
+		hit, ship := board.Attack(shot.Target)
+		actual := Discrepancy{Shot: shot, Result: ShotMiss}
+		if hit {
+			actual.ShipName = ship.Name
+			actual.Result = ShotHit
+			if ship.IsSunk() {
+				actual.Result = ShotSunk
+			}
+		}
+
+		if actual.Result != shot.Result || (shot.Result == ShotSunk && actual.ShipName != shot.Ship) {
+			discrepancies = append(discrepancies, actual)
+		}
+	}
+
+	return discrepancies, nil
+}
+
+// revealedBoard places a revealed fleet on an empty board, checking that it
+// is the expected fleet and a legal layout under the placement rules
+func revealedBoard(reveal FleetReveal, rows, cols int, fleet Fleet, rules PlacementRules) (*Board, error) {
+	if len(reveal.Ships) != len(fleet.Ships) {
+		return nil, fmt.Errorf("revealed fleet has %d ships, expected %d", len(reveal.Ships), len(fleet.Ships))
+	}
+
+	board := newRuledBoard(rows, cols, rules)
+	for i, revealed := range reveal.Ships {
+		ship := NewShip(fleet.Ships[i])
+		if revealed.Name != ship.Name || len(revealed.Positions) != ship.Length {
+			return nil, fmt.Errorf("revealed ship %d is a %s of length %d, expected a %s", i+1, revealed.Name, len(revealed.Positions), ship.Name)
+		}
+
+		// Cells must be in a straight, unbroken line that fits on the board
+		orientation := Horizontal
+		if ship.Length > 1 && revealed.Positions[1].Col == revealed.Positions[0].Col {
+			orientation = Vertical
+		}
+		for j, pos := range board.getShipPositions(revealed.Positions[0], ship.Length, orientation) {
+			if pos != revealed.Positions[j] {
+				return nil, fmt.Errorf("revealed %s is not in a straight line", ship.Name)
+			}
+		}
+		if !board.PlaceShip(ship, revealed.Positions[0], orientation) {
+			if rules.NoTouch {
+				return nil, fmt.Errorf("revealed %s is off the board, overlaps or touches another ship", ship.Name)
+			}
+			return nil, fmt.Errorf("revealed %s is off the board or overlaps another ship", ship.Name)
+		}
+	}
+
+	return board, nil
+}
+
+// VerifyOpponentFleet checks a remote opponent's revealed fleet against the
+// commitment they published and the results they reported for every shot
+// fired at them, including the cells of ships they reported sunk
+func (g *Game) VerifyOpponentFleet(commitment string, reveal FleetReveal) ([]Discrepancy, error) {
+	var shots []ShotRecord
+	for _, shot := range g.Shots.Shots {
+		if shot.Shooter == PlayerSide {
+			shots = append(shots, shot)
+		}
+	}
+
+	discrepancies, err := VerifyFleet(commitment, reveal, g.Rows, g.Cols, g.Fleet, g.Rules, shots)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sunk := range g.ComputerBoard.Ships {
+		for _, revealed := range reveal.Ships {
+			if revealed.Name == sunk.Name && !slices.Equal(revealed.Positions, sunk.Positions) {
+				return nil, fmt.Errorf("%s was reported sunk in a different place than revealed", sunk.Name)
+			}
+		}
+	}
+
+	return discrepancies, nil
+}
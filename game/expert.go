@@ -0,0 +1,124 @@
+package game
+
+import "math/rand"
+
+// expertStrategy implements expert difficulty - probability density targeting.
+// Every legal placement of every unsunk ship that is consistent with the known
+// hits, misses and sunk ships adds to the count of the cells it covers, and the
+// untried cell with the highest count is chosen. A placement cannot cover more
+// cells of an unresolved salvo than that salvo hit.
+type expertStrategy struct{}
+
+// NextTarget fires at the cell most likely to hold a ship
+func (expertStrategy) NextTarget(view FogView, rng *rand.Rand) Position {
+	// While there are hits on ships that are still afloat, only placements
+	// through those hits are considered
+	if pos, ok := densityTarget(view, rng, true); ok {
+		return pos
+	}
+	if pos, ok := densityTarget(view, rng, false); ok {
+		return pos
+	}
+	return easyStrategy{}.NextTarget(view, rng)
+}
+
+// NextSalvo picks each shot of a salvo in turn
+func (s expertStrategy) NextSalvo(view FogView, shots int, rng *rand.Rand) []Position {
+	return SequentialSalvo(s, view, shots, rng)
+}
+
+// densityTarget returns the untried cell covered by the most consistent ship
+// placements. In targeting mode only placements through unresolved hits count,
+// weighted by the number of hits they are expected to cover.
+func densityTarget(view FogView, rng *rand.Rand, targeting bool) (Position, bool) {
+	rows, cols := view.Rows(), view.Cols()
+
+	counts := make([][]float64, rows)
+	for i := range counts {
+		counts[i] = make([]float64, cols)
+	}
+
+	// Which unresolved salvo each uncertain cell belongs to
+	groups := view.Groups()
+	groupOf := map[Position]int{}
+	for i, g := range groups {
+		for _, p := range g.Cells {
+			groupOf[p] = i
+		}
+	}
+	covered := make([]int, len(groups))
+
+	for _, length := range view.RemainingShipLengths() {
+		for row := 0; row < rows; row++ {
+			for col := 0; col < cols; col++ {
+				for _, orientation := range []Orientation{Horizontal, Vertical} {
+					cells := make([]Position, length)
+					for i := range cells {
+						if orientation == Horizontal {
+							cells[i] = Position{Row: row, Col: col + i}
+						} else {
+							cells[i] = Position{Row: row + i, Col: col}
+						}
+					}
+
+					// Cells belonging to sunk ships are known and cannot hold another
+					// ship, nor can cells the placement rules keep clear of them
+					legal := true
+					hits := 0.0
+					clear(covered)
+					for _, p := range cells {
+						if !view.IsValidPosition(p) || view.Cell(p) == FogMiss || view.Cell(p) == FogSunk || view.RuledOut(p) {
+							legal = false
+							break
+						}
+						if i, ok := groupOf[p]; ok {
+							covered[i]++
+							if covered[i] > groups[i].Hits {
+								legal = false
+								break
+							}
+						}
+						hits += view.HitChance(p)
+					}
+					if !legal {
+						continue
+					}
+
+					weight := 1.0
+					if targeting {
+						if hits == 0 {
+							continue
+						}
+						weight = hits
+					}
+
+					for _, p := range cells {
+						if view.IsTargetable(p) {
+							counts[p.Row][p.Col] += weight
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Pick the highest count, breaking ties randomly
+	best := 0.0
+	candidates := []Position{}
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			if counts[row][col] > best {
+				best = counts[row][col]
+				candidates = candidates[:0]
+			}
+			if counts[row][col] == best && best > 0 {
+				candidates = append(candidates, Position{Row: row, Col: col})
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Position{}, false
+	}
+	return candidates[rng.Intn(len(candidates))], true
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ShipSpec describes one ship of a fleet
+type ShipSpec struct {
+	Name   string `json:"name"`
+	Length int    `json:"length"`
+}
+
+// Fleet is the set of ships each side places, in placement order
+type Fleet struct {
+	Name  string     `json:"name"`
+	Ships []ShipSpec `json:"ships"`
+}
+
+// Built-in fleets
+var (
+	// MiltonBradleyFleet is the fleet of the 1990 Milton Bradley edition, and
+	// the default fleet
+	MiltonBradleyFleet = Fleet{
+		Name: "Milton Bradley 1990",
+		Ships: []ShipSpec{
+			{Name: "Carrier", Length: 5},
+			{Name: "Battleship", Length: 4},
+			{Name: "Cruiser", Length: 3},
+			{Name: "Submarine", Length: 3},
+			{Name: "Destroyer", Length: 2},
+		},
+	}
+
+	// ClassicFleet is the fleet of the current Hasbro edition
+	ClassicFleet = Fleet{
+		Name: "Classic Hasbro",
+		Ships: []ShipSpec{
+			{Name: "Carrier", Length: 5},
+			{Name: "Battleship", Length: 4},
+			{Name: "Destroyer", Length: 3},
+			{Name: "Submarine", Length: 3},
+			{Name: "Patrol Boat", Length: 2},
+		},
+	}
+
+	// SmallFleet is a lighter fleet that leaves room to manoeuvre on an 8x8 board
+	SmallFleet = Fleet{
+		Name: "Small Fleet",
+		Ships: []ShipSpec{
+			{Name: "Battleship", Length: 4},
+			{Name: "Cruiser", Length: 3},
+			{Name: "Destroyer", Length: 2},
+			{Name: "Patrol Boat", Length: 2},
+		},
+	}
+)
+
+// DefaultFleet is the fleet used when none is chosen
+var DefaultFleet = MiltonBradleyFleet
+
+// FleetPresets returns the built-in fleets in menu order
+func FleetPresets() []Fleet {
+	return []Fleet{MiltonBradleyFleet, ClassicFleet, SmallFleet}
+}
+
+// LoadFleet reads a fleet from JSON, for example:
+//
+//	{"name": "Armada", "ships": [{"name": "Dreadnought", "length": 6}, {"name": "Frigate", "length": 2}]}
+func LoadFleet(r io.Reader) (Fleet, error) {
+	var fleet Fleet
+	if err := json.NewDecoder(r).Decode(&fleet); err != nil {
+		return Fleet{}, fmt.Errorf("invalid fleet file: %w", err)
+	}
+	if fleet.Name == "" {
+		fleet.Name = "Custom"
+	}
+	if err := fleet.Validate(); err != nil {
+		return Fleet{}, err
+	}
+	return fleet, nil
+}
+
+// Validate checks that a fleet has at least one ship and that every ship has
+// a unique name and a positive length
+func (f Fleet) Validate() error {
+	if len(f.Ships) == 0 {
+		return errors.New("fleet has no ships")
+	}
+
+	seen := map[string]bool{}
+	for _, spec := range f.Ships {
+		name := strings.TrimSpace(spec.Name)
+		if name == "" || name != spec.Name || strings.Contains(name, "  ") {
+			return fmt.Errorf("invalid ship name %q", spec.Name)
+		}
+		if seen[name] {
+			return fmt.Errorf("fleet has two ships named %q", name)
+		}
+		seen[name] = true
+		if spec.Length <= 0 {
+			return fmt.Errorf("%s must have a positive length, got %d", name, spec.Length)
+		}
+	}
+	return nil
+}
+
+// Lengths returns the length of every ship in the fleet, in order
+func (f Fleet) Lengths() []int {
+	lengths := make([]int, len(f.Ships))
+	for i, spec := range f.Ships {
+		lengths[i] = spec.Length
+	}
+	return lengths
+}
+
+// Spec returns the ship with the given name
+func (f Fleet) Spec(name string) (ShipSpec, bool) {
+	for _, spec := range f.Ships {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return ShipSpec{}, false
+}
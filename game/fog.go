@@ -0,0 +1,247 @@
+package game
+
+// FogCell is what an opponent is allowed to know about a cell
+type FogCell int
+
+const (
+	FogUnknown FogCell = iota
+	FogMiss
+	FogHit  // Hit on a ship that is still afloat
+	FogSunk // Part of a sunk ship, whose identity is known
+	// Fired at in a salvo of which only the number of hits was announced
+	FogUncertain
+)
+
+// SalvoGroup is the cells of a salvo whose results were announced only as a
+// number of hits, and which are still unresolved: some but not all of them hit
+type SalvoGroup struct {
+	Cells []Position
+	Hits  int
+}
+
+// FogView is an opponent's view of a board: a snapshot showing only what has
+// been revealed by attacks. It holds no reference to the board, so unhit ship
+// cells cannot be recovered from it. Strategies and remote players only ever
+// get a FogView.
+type FogView struct {
+	rows      int
+	cols      int
+	cells     [][]FogCell
+	sunk      map[Position]string // Name of the sunk ship covering each FogSunk cell
+	remaining []int               // Lengths of the ships still afloat
+	pending   []Position          // Cells already chosen for the salvo being built
+	noTouch   bool                // Ships are known not to touch each other
+	groups    []SalvoGroup        // Unresolved salvos covering every FogUncertain cell
+}
+
+// NewFogView takes a snapshot of what an opponent can see of a board
+func NewFogView(board *Board) FogView {
+	v := FogView{
+		rows:      board.Rows,
+		cols:      board.Cols,
+		cells:     make([][]FogCell, board.Rows),
+		sunk:      map[Position]string{},
+		remaining: []int{},
+		noTouch:   board.Rules.NoTouch,
+	}
+
+	for row := range v.cells {
+		v.cells[row] = make([]FogCell, board.Cols)
+		for col := range v.cells[row] {
+			switch board.Grid[row][col] {
+			case Hit:
+				v.cells[row][col] = FogHit
+			case Miss:
+				v.cells[row][col] = FogMiss
+			}
+		}
+	}
+
+	for _, ship := range board.Ships {
+		if !ship.IsSunk() {
+			v.remaining = append(v.remaining, ship.Length)
+			continue
+		}
+		for _, p := range ship.Positions {
+			v.cells[p.Row][p.Col] = FogSunk
+			v.sunk[p] = ship.Name
+		}
+	}
+
+	return v
+}
+
+// newCountedFogView takes a snapshot of what the shooter knows of a board when
+// only the number of hits of each salvo is announced. A salvo that hit with
+// every shot or none is resolved, the rest become SalvoGroups. Sinking a ship
+// is announced by name but not where it lay, so the view keeps the lengths of
+// the ships still afloat and has no FogSunk cells, and nothing is ruled out by
+// the no-touch rule.
+func newCountedFogView(board *Board, shots *ShotLedger, shooter Side) FogView {
+	v := FogView{
+		rows:      board.Rows,
+		cols:      board.Cols,
+		cells:     make([][]FogCell, board.Rows),
+		sunk:      map[Position]string{},
+		remaining: []int{},
+		noTouch:   board.Rules.NoTouch,
+	}
+	for row := range v.cells {
+		v.cells[row] = make([]FogCell, board.Cols)
+	}
+
+	for _, ship := range board.Ships {
+		if !ship.IsSunk() {
+			v.remaining = append(v.remaining, ship.Length)
+		}
+	}
+
+	for _, volley := range shots.Volleys() {
+		if volley[0].Shooter != shooter {
+			continue
+		}
+
+		group := SalvoGroup{}
+		for _, shot := range volley {
+			group.Cells = append(group.Cells, shot.Target)
+			if shot.Result != ShotMiss {
+				group.Hits++
+			}
+		}
+
+		cell := FogUncertain
+		switch group.Hits {
+		case 0:
+			cell = FogMiss
+		case len(group.Cells):
+			cell = FogHit
+		default:
+			v.groups = append(v.groups, group)
+		}
+		for _, p := range group.Cells {
+			v.cells[p.Row][p.Col] = cell
+		}
+	}
+
+	return v
+}
+
+// Rows returns the height of the board
+func (v FogView) Rows() int {
+	return v.rows
+}
+
+// Cols returns the width of the board
+func (v FogView) Cols() int {
+	return v.cols
+}
+
+// IsValidPosition checks if a position is within board bounds
+func (v FogView) IsValidPosition(pos Position) bool {
+	return pos.Row >= 0 && pos.Row < v.rows && pos.Col >= 0 && pos.Col < v.cols
+}
+
+// Cell returns what is known about a cell
+func (v FogView) Cell(pos Position) FogCell {
+	if !v.IsValidPosition(pos) {
+		return FogUnknown
+	}
+	return v.cells[pos.Row][pos.Col]
+}
+
+// SunkShip returns the name of the sunk ship covering a cell, or an empty
+// string if the cell is not part of a sunk ship
+func (v FogView) SunkShip(pos Position) string {
+	return v.sunk[pos]
+}
+
+// RemainingShipLengths returns the lengths of the ships that are still afloat
+func (v FogView) RemainingShipLengths() []int {
+	lengths := make([]int, len(v.remaining))
+	copy(lengths, v.remaining)
+	return lengths
+}
+
+// Groups returns the salvos that are still unresolved
+func (v FogView) Groups() []SalvoGroup {
+	groups := make([]SalvoGroup, len(v.groups))
+	copy(groups, v.groups)
+	return groups
+}
+
+// group returns the unresolved salvo covering pos
+func (v FogView) group(pos Position) (SalvoGroup, bool) {
+	for _, g := range v.groups {
+		for _, p := range g.Cells {
+			if p == pos {
+				return g, true
+			}
+		}
+	}
+	return SalvoGroup{}, false
+}
+
+// HitChance returns how likely it is that a cell that has been fired at hit a
+// ship: 1 for a known hit, the share of hits in its salvo for an uncertain
+// cell, and 0 otherwise
+func (v FogView) HitChance(pos Position) float64 {
+	switch v.Cell(pos) {
+	case FogHit, FogSunk:
+		return 1
+	case FogUncertain:
+		if g, ok := v.group(pos); ok {
+			return float64(g.Hits) / float64(len(g.Cells))
+		}
+	}
+	return 0
+}
+
+// RuledOut returns true if an unknown cell cannot hold a ship because ships
+// may not touch and it is next to a sunk ship
+func (v FogView) RuledOut(pos Position) bool {
+	if !v.noTouch || v.Cell(pos) != FogUnknown || !v.IsValidPosition(pos) {
+		return false
+	}
+	for _, p := range neighbours(pos, v.rows, v.cols) {
+		if v.cells[p.Row][p.Col] == FogSunk {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTargetable returns true if the cell is on the board, has not been
+// attacked, could hold a ship and is not already part of the salvo being built
+func (v FogView) IsTargetable(pos Position) bool {
+	if v.Cell(pos) != FogUnknown || !v.IsValidPosition(pos) || v.RuledOut(pos) {
+		return false
+	}
+	for _, p := range v.pending {
+		if p == pos {
+			return false
+		}
+	}
+	return true
+}
+
+// WithPending returns a copy of the view in which pos is no longer targetable,
+// for building a salvo one shot at a time
+func (v FogView) WithPending(pos Position) FogView {
+	pending := make([]Position, len(v.pending), len(v.pending)+1)
+	copy(pending, v.pending)
+	v.pending = append(pending, pos)
+	return v
+}
+
+// targetCount returns the number of cells that can still be targeted
+func (v FogView) targetCount() int {
+	count := 0
+	for row := 0; row < v.rows; row++ {
+		for col := 0; col < v.cols; col++ {
+			if v.IsTargetable(Position{Row: row, Col: col}) {
+				count++
+			}
+		}
+	}
+	return count
+}
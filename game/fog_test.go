@@ -0,0 +1,123 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fogTestBoard builds a no-touch board with a sunk destroyer, a damaged
+// cruiser, two misses and an unhit battleship placed at battleship
+func fogTestBoard(t *testing.T, battleship Position) *Board {
+	t.Helper()
+	b := NewBoard(8, 8)
+	b.Rules = PlacementRules{NoTouch: true}
+
+	ships := []struct {
+		spec        ShipSpec
+		pos         Position
+		orientation Orientation
+	}{
+		{ShipSpec{Name: "Destroyer", Length: 2}, Position{Row: 0, Col: 0}, Horizontal},
+		{ShipSpec{Name: "Cruiser", Length: 3}, Position{Row: 4, Col: 4}, Horizontal},
+		{ShipSpec{Name: "Battleship", Length: 4}, battleship, Horizontal},
+	}
+	for _, s := range ships {
+		if !b.PlaceShip(NewShip(s.spec), s.pos, s.orientation) {
+			t.Fatalf("cannot place %s at %s", s.spec.Name, s.pos)
+		}
+	}
+
+	for _, pos := range []Position{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 4, Col: 4}, {Row: 2, Col: 2}, {Row: 5, Col: 0}} {
+		b.Attack(pos)
+	}
+	return b
+}
+
+func TestFogViewHidesUnhitShips(t *testing.T) {
+	boardA := fogTestBoard(t, Position{Row: 7, Col: 0})
+	boardB := fogTestBoard(t, Position{Row: 7, Col: 4})
+	if reflect.DeepEqual(boardA.Grid, boardB.Grid) {
+		t.Fatal("the boards should differ in where the battleship is")
+	}
+	a, b := NewFogView(boardA), NewFogView(boardB)
+
+	if !reflect.DeepEqual(a.RemainingShipLengths(), b.RemainingShipLengths()) {
+		t.Errorf("RemainingShipLengths() = %v and %v", a.RemainingShipLengths(), b.RemainingShipLengths())
+	}
+
+	// Every cell, including some just off the board
+	for row := -1; row <= 8; row++ {
+		for col := -1; col <= 8; col++ {
+			pos := Position{Row: row, Col: col}
+			if a.Cell(pos) != b.Cell(pos) {
+				t.Errorf("Cell(%v) = %v and %v", pos, a.Cell(pos), b.Cell(pos))
+			}
+			if a.SunkShip(pos) != b.SunkShip(pos) {
+				t.Errorf("SunkShip(%v) = %q and %q", pos, a.SunkShip(pos), b.SunkShip(pos))
+			}
+			if a.IsTargetable(pos) != b.IsTargetable(pos) {
+				t.Errorf("IsTargetable(%v) = %v and %v", pos, a.IsTargetable(pos), b.IsTargetable(pos))
+			}
+			if a.RuledOut(pos) != b.RuledOut(pos) {
+				t.Errorf("RuledOut(%v) = %v and %v", pos, a.RuledOut(pos), b.RuledOut(pos))
+			}
+		}
+	}
+}
+
+func TestFogViewShowsAttacks(t *testing.T) {
+	v := NewFogView(fogTestBoard(t, Position{Row: 7, Col: 0}))
+
+	tests := []struct {
+		pos        Position
+		cell       FogCell
+		sunk       string
+		targetable bool
+		ruledOut   bool
+	}{
+		{Position{Row: 0, Col: 0}, FogSunk, "Destroyer", false, false},
+		{Position{Row: 4, Col: 4}, FogHit, "", false, false},
+		{Position{Row: 2, Col: 2}, FogMiss, "", false, false},
+		{Position{Row: 1, Col: 1}, FogUnknown, "", false, true},  // Next to the sunk destroyer
+		{Position{Row: 4, Col: 5}, FogUnknown, "", true, false},  // Unhit cruiser cell
+		{Position{Row: 7, Col: 0}, FogUnknown, "", true, false},  // Unhit battleship cell
+		{Position{Row: 8, Col: 0}, FogUnknown, "", false, false}, // Off the board
+	}
+	for _, tt := range tests {
+		if got := v.Cell(tt.pos); got != tt.cell {
+			t.Errorf("Cell(%v) = %v, want %v", tt.pos, got, tt.cell)
+		}
+		if got := v.SunkShip(tt.pos); got != tt.sunk {
+			t.Errorf("SunkShip(%v) = %q, want %q", tt.pos, got, tt.sunk)
+		}
+		if got := v.IsTargetable(tt.pos); got != tt.targetable {
+			t.Errorf("IsTargetable(%v) = %v, want %v", tt.pos, got, tt.targetable)
+		}
+		if got := v.RuledOut(tt.pos); got != tt.ruledOut {
+			t.Errorf("RuledOut(%v) = %v, want %v", tt.pos, got, tt.ruledOut)
+		}
+	}
+
+	if got, want := v.RemainingShipLengths(), []int{3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemainingShipLengths() = %v, want %v", got, want)
+	}
+}
+
+func TestCountedFogViewKeepsOnlyLengthsOfSunkShips(t *testing.T) {
+	settings := salvoSettings(SalvoRule{}, true)
+	settings.Rules.NoTouch = true
+	g := playComputers(t, settings, 1)
+	view := g.OpponentView(ComputerSide)
+
+	if got, want := view.RemainingShipLengths(), NewFogView(g.PlayerBoard).RemainingShipLengths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RemainingShipLengths() = %v, want %v", got, want)
+	}
+	for row := 0; row < g.Rows; row++ {
+		for col := 0; col < g.Cols; col++ {
+			pos := Position{Row: row, Col: col}
+			if view.Cell(pos) == FogSunk || view.SunkShip(pos) != "" || view.RuledOut(pos) {
+				t.Errorf("%s is shown as part of or next to a sunk ship", pos)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"errors"
 	"math/rand"
 	"time"
 )
@@ -14,33 +15,77 @@ const (
 	PlayerTurnPhase
 	ComputerTurnPhase
 	GameOverPhase
+	HandoffPhase     // Hot-seat curtain while the keyboard changes hands
+	WaitingPhase     // Waiting on a remote opponent before play can continue
+	FleetReviewPhase // Every ship is placed, awaiting the player's confirmation
 )
 
-// Difficulty represents the AI difficulty level
+// GameMode represents who controls each side
+type GameMode int
+
+const (
+	VsComputer         GameMode = iota // A human player against Captain Claude
+	ComputerVsComputer                 // Both sides are played by the computer
+	HotSeat                            // Two human players sharing one keyboard
+	Network                            // A human player against a remote peer
+)
+
+// Difficulty represents the built-in AI difficulty levels, each of which is
+// registered as a Strategy under its String name
 type Difficulty int
 
 const (
 	Easy Difficulty = iota
 	Normal
 	Hard
+	Expert
 )
 
+// String returns the display name of the difficulty
+func (d Difficulty) String() string {
+	switch d {
+	case Easy:
+		return "Easy"
+	case Normal:
+		return "Normal"
+	case Hard:
+		return "Hard"
+	case Expert:
+		return "Expert"
+	}
+	return "Unknown"
+}
+
 // Game represents the game state
 type Game struct {
 	PlayerBoard      *Board
 	ComputerBoard    *Board
 	Phase            GamePhase
-	BoardSize        int
+	Rows             int
+	Cols             int
 	CurrentShip      int // For placement phase
-	ShipTypes        []ShipType
+	Fleet            Fleet // Ships each side places
+	Rules            PlacementRules
 	Winner           string
 	LastMessage      string
 	ClaudeThinking   string
-	Difficulty       Difficulty
+	ComputerStrategy string     // Name of the registered Strategy Claude plays with
+	Mode             GameMode
+	PlayerStrategy   string     // Strategy of the player side when it is computer-controlled
 	Random           *rand.Rand
+	Seed             int64      // Seed the random source was created from
 	SalvoMode        bool       // Enable salvo mode (multiple shots per turn)
+	HitCountsOnly    bool       // Salvo results announce how many shots hit, not which
+	SalvoRule        SalvoRule  // How many shots each salvo has
 	PlayerSalvo      []Position // Queued shots for player
-	SalvoMessages    []string   // Messages from salvo attacks
+	Shots            ShotLedger // Every attack made by both sides, in order
+	Turn             int        // Current turn number, starting at 1 once battle begins
+	Active           Side       // Side whose human player is placing or firing
+	undo             []layout   // Layouts of the active player's fleet before each placement change
+	redo             []layout   // Layouts undone since the last placement change
+	rng              *countingSource
+	remoteShot       *Position  // Shot fired at a remote opponent, awaiting its result
+	layoutRandom     *rand.Rand // Lays out human fleets, apart from Random so the seed still fixes every computer shot
 }
 
 // Claude thinking messages
@@ -59,59 +104,84 @@ var thinkingMessages = []string{
 	"Ruminating",
 }
 
-// NewGame creates a new game
-func NewGame(boardSize int) *Game {
-	g := &Game{
-		PlayerBoard:   NewBoard(boardSize),
-		ComputerBoard: NewBoard(boardSize),
-		Phase:         PlacementPhase,
-		BoardSize:     boardSize,
-		CurrentShip:   0,
-		ShipTypes: []ShipType{
-			Carrier,
-			Battleship,
-			Cruiser,
-			Submarine,
-			Destroyer,
-		},
-		Random: rand.New(rand.NewSource(time.Now().UnixNano())),
+// newGame creates a game in placement phase with empty boards
+func newGame(settings Settings, seed int64) *Game {
+	rng := newCountingSource(seed)
+
+	return &Game{
+		PlayerBoard:      newRuledBoard(settings.Rows, settings.Cols, settings.Rules),
+		ComputerBoard:    newRuledBoard(settings.Rows, settings.Cols, settings.Rules),
+		Phase:            PlacementPhase,
+		Rows:             settings.Rows,
+		Cols:             settings.Cols,
+		CurrentShip:      0,
+		Fleet:            settings.Fleet,
+		Rules:            settings.Rules,
+		Mode:             settings.Mode,
+		ComputerStrategy: settings.Strategy,
+		PlayerStrategy:   settings.PlayerStrategy,
+		SalvoMode:        settings.SalvoMode,
+		HitCountsOnly:    settings.HitCountsOnly,
+		SalvoRule:        settings.SalvoRule,
+		Random:           rand.New(rng),
+		Seed:             seed,
+		rng:              rng,
 	}
-
-	// Place computer ships randomly
-	g.placeComputerShips()
-
-	return g
 }
 
-// placeComputerShips randomly places all ships for the computer
-func (g *Game) placeComputerShips() {
-	for _, shipType := range g.ShipTypes {
-		ship := NewShip(shipType)
-		placed := false
+// newRuledBoard creates an empty board that enforces the placement rules
+func newRuledBoard(rows, cols int, rules PlacementRules) *Board {
+	b := NewBoard(rows, cols)
+	b.Rules = rules
+	return b
+}
 
-		for !placed {
-			row := g.Random.Intn(g.BoardSize)
-			col := g.Random.Intn(g.BoardSize)
-			orientation := Orientation(g.Random.Intn(2))
+// IsPlacing returns true while the active player is placing or reviewing
+// their fleet
+func (g *Game) IsPlacing() bool {
+	return g.Phase == PlacementPhase || g.Phase == FleetReviewPhase
+}
 
-			pos := Position{Row: row, Col: col}
-			placed = g.ComputerBoard.PlaceShip(ship, pos, orientation)
-		}
-	}
+// RandomizePlacement places the active player's whole fleet at random,
+// replacing any ships they have placed so far, and moves on to the review.
+// The fleet is left as it was if no layout is found. The layout is not drawn
+// from Random, so the seed still decides every computer shot.
+func (g *Game) RandomizePlacement() error {
+	if !g.IsPlacing() {
+		return errors.New("ships can only be placed before the battle")
+	}
+
+	before := g.layout()
+	board := g.Board(g.Active)
+	board.clear()
+	if g.layoutRandom == nil {
+		g.layoutRandom = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	if err := RandomFleet(board, g.Fleet, g.Rules, g.layoutRandom); err != nil {
+		g.setLayout(before)
+		return err
+	}
+	g.recordPlacement(before)
+	g.CurrentShip = len(g.Fleet.Ships)
+	g.Phase = FleetReviewPhase
+	g.LastMessage = ""
+	return nil
 }
 
-// PlacePlayerShip places the current ship for the player
+// PlacePlayerShip places the current ship for the active player
 func (g *Game) PlacePlayerShip(pos Position, orientation Orientation) bool {
-	if g.Phase != PlacementPhase || g.CurrentShip >= len(g.ShipTypes) {
+	if g.Phase != PlacementPhase || g.CurrentShip >= len(g.Fleet.Ships) {
 		return false
 	}
 
-	ship := NewShip(g.ShipTypes[g.CurrentShip])
-	if g.PlayerBoard.PlaceShip(ship, pos, orientation) {
+	before := g.layout()
+	ship := NewShip(g.Fleet.Ships[g.CurrentShip])
+	if g.Board(g.Active).PlaceShip(ship, pos, orientation) {
+		g.recordPlacement(before)
+		g.LastMessage = ""
 		g.CurrentShip++
-		if g.CurrentShip >= len(g.ShipTypes) {
-			g.Phase = PlayerTurnPhase
-			g.LastMessage = "All ships placed! Your turn to attack!"
+		if g.CurrentShip >= len(g.Fleet.Ships) {
+			g.Phase = FleetReviewPhase
 		}
 		return true
 	}
@@ -119,15 +189,104 @@ func (g *Game) PlacePlayerShip(pos Position, orientation Orientation) bool {
 	return false
 }
 
+// ConfirmFleet ends the review of the active player's complete fleet
+func (g *Game) ConfirmFleet() bool {
+	if g.Phase != FleetReviewPhase {
+		return false
+	}
+	g.finishPlacement()
+	return true
+}
+
+// finishPlacement moves on once the active player's fleet is confirmed. In a
+// hot-seat game the second player places next, and battle begins after both.
+func (g *Game) finishPlacement() {
+	g.undo = nil
+	g.redo = nil
+
+	if g.Mode == Network {
+		g.Phase = WaitingPhase
+		g.LastMessage = "Fleet ready! Waiting for your opponent..."
+		return
+	}
+
+	if g.Mode != HotSeat {
+		g.Phase = PlayerTurnPhase
+		g.Turn = 1
+		g.LastMessage = "All ships placed! Your turn to attack!"
+		return
+	}
+
+	g.LastMessage = g.SideName(g.Active) + "'s fleet is ready!"
+	g.Active = g.Active.Opponent()
+	g.CurrentShip = 0
+	if g.Active == PlayerSide {
+		g.Turn = 1
+	}
+	g.Phase = HandoffPhase
+}
+
+// EndHandoff lifts the hot-seat curtain once the next player has the keyboard
+func (g *Game) EndHandoff() {
+	if g.Phase != HandoffPhase {
+		return
+	}
+
+	// The second player places or reviews their fleet before the first turn
+	if g.Turn == 0 {
+		g.CurrentShip = len(g.Board(g.Active).Ships)
+		g.Phase = PlacementPhase
+		if g.CurrentShip == len(g.Fleet.Ships) {
+			g.Phase = FleetReviewPhase
+		}
+		g.LastMessage = ""
+		return
+	}
+
+	g.Phase = PlayerTurnPhase
+	g.LastMessage = "Your turn, " + g.SideName(g.Active) + "! Select a target and fire!"
+}
+
+// targetBoard returns the board the active player fires at
+func (g *Game) targetBoard() *Board {
+	return g.Board(g.Active.Opponent())
+}
+
+// endPlayerTurn passes play on after a human player has fired
+func (g *Game) endPlayerTurn() {
+	if g.Mode != HotSeat {
+		g.Phase = ComputerTurnPhase
+		g.ClaudeThinking = g.GetRandomThinkingMessage()
+		return
+	}
+
+	g.Active = g.Active.Opponent()
+	if g.Active == PlayerSide {
+		g.Turn++
+	}
+	g.Phase = HandoffPhase
+}
+
+// declarePlayerVictory ends the game with the active human player as winner
+func (g *Game) declarePlayerVictory() {
+	g.Phase = GameOverPhase
+	if g.Mode == HotSeat {
+		g.Winner = g.SideName(g.Active)
+		g.LastMessage = "Victory! " + g.Winner + " sunk " + g.SideName(g.Active.Opponent()) + "'s fleet!"
+		return
+	}
+	g.Winner = "Player"
+	g.LastMessage = "Victory! You sunk " + g.opponentName() + "'s fleet!"
+}
+
 // GetCurrentShipForPlacement returns the ship currently being placed
 func (g *Game) GetCurrentShipForPlacement() *Ship {
-	if g.CurrentShip >= len(g.ShipTypes) {
+	if g.CurrentShip >= len(g.Fleet.Ships) {
 		return nil
 	}
-	return NewShip(g.ShipTypes[g.CurrentShip])
+	return NewShip(g.Fleet.Ships[g.CurrentShip])
 }
 
-// PlayerAttack performs a player attack on the computer's board
 // GetRemainingShips returns the number of unsunk ships for a board
 func (g *Game) GetRemainingShips(playerBoard bool) int {
 	board := g.ComputerBoard
@@ -149,17 +308,17 @@ func (g *Game) GetSalvoShotsRemaining() int {
 	if !g.SalvoMode {
 		return 0
 	}
-	maxShots := g.GetRemainingShips(true)
-	return maxShots - len(g.PlayerSalvo)
+	return g.SalvoShots(g.Active) - len(g.PlayerSalvo)
 }
 
 // IsValidSalvoTarget checks if a position is a valid target for salvo
 func (g *Game) IsValidSalvoTarget(pos Position) bool {
-	if !g.ComputerBoard.IsValidPosition(pos) {
+	target := g.targetBoard()
+	if !target.IsValidPosition(pos) {
 		return false
 	}
 
-	cell := g.ComputerBoard.GetCell(pos)
+	cell := target.GetCell(pos)
 	if cell == Hit || cell == Miss {
 		return false
 	}
@@ -192,68 +351,38 @@ func (g *Game) QueueSalvoShot(pos Position) bool {
 	return true
 }
 
-// ExecutePlayerSalvo fires all queued shots
-func (g *Game) ExecutePlayerSalvo() {
+// ExecutePlayerSalvo fires all queued shots and returns what each of them hit
+func (g *Game) ExecutePlayerSalvo() SalvoResult {
+	result := SalvoResult{Shooter: g.Active}
 	if !g.SalvoMode || len(g.PlayerSalvo) == 0 {
-		return
+		return result
 	}
 
-	g.SalvoMessages = []string{}
-	hits := 0
-	misses := 0
-	sunkShips := []string{}
-
+	target := g.targetBoard()
 	for _, pos := range g.PlayerSalvo {
-		hit, ship := g.ComputerBoard.Attack(pos)
-
-		if hit {
-			hits++
-			if ship != nil && ship.IsSunk() {
-				sunkShips = append(sunkShips, ship.Name)
-			}
-		} else {
-			misses++
-		}
+		hit, ship := target.Attack(pos)
+		result.Shots = append(result.Shots, g.recordShot(g.Active, pos, hit, ship))
 	}
 
-	// Build message
-	msg := ""
-	if hits > 0 {
-		msg += "Hits: " + string(rune('0'+hits))
-	}
-	if misses > 0 {
-		if msg != "" {
-			msg += " | "
-		}
-		msg += "Misses: " + string(rune('0'+misses))
-	}
-	if len(sunkShips) > 0 {
-		if msg != "" {
-			msg += " | "
-		}
-		msg += "Sunk: "
-		for i, name := range sunkShips {
-			if i > 0 {
-				msg += ", "
-			}
-			msg += name
-		}
-	}
-
-	g.LastMessage = msg
+	g.LastMessage = result.Summary()
 	g.PlayerSalvo = []Position{}
 
-	if g.ComputerBoard.AllShipsSunk() {
-		g.Phase = GameOverPhase
-		g.Winner = "Player"
-		g.LastMessage = "Victory! You sunk all enemy ships!"
-		return
+	if target.AllShipsSunk() {
+		g.declarePlayerVictory()
+		return result
+	}
+
+	// A chain goes on for as long as it hits
+	if g.SalvoRule.Kind == ChainShots && result.Hits() > 0 {
+		g.LastMessage += " | Fire again!"
+		return result
 	}
 
-	g.Phase = ComputerTurnPhase
-	g.ClaudeThinking = g.GetRandomThinkingMessage()
+	g.endPlayerTurn()
+	return result
 }
 
+// PlayerAttack performs the active player's attack on the opponent's board
 func (g *Game) PlayerAttack(pos Position) bool {
 	if g.Phase != PlayerTurnPhase {
 		return false
@@ -264,43 +393,57 @@ func (g *Game) PlayerAttack(pos Position) bool {
 		return g.QueueSalvoShot(pos)
 	}
 
+	if g.Mode == Network {
+		return g.fireRemoteShot(pos)
+	}
+
 	// Validate position
-	if !g.ComputerBoard.IsValidPosition(pos) {
+	target := g.targetBoard()
+	if !target.IsValidPosition(pos) {
 		g.LastMessage = "Invalid position!"
 		return false
 	}
 
 	// Prevent attacking the same coordinate twice
-	cell := g.ComputerBoard.GetCell(pos)
+	cell := target.GetCell(pos)
 	if cell == Hit || cell == Miss {
 		g.LastMessage = "You already attacked that position!"
 		return false
 	}
 
-	hit, ship := g.ComputerBoard.Attack(pos)
+	hit, ship := target.Attack(pos)
+	g.recordShot(g.Active, pos, hit, ship)
 
 	if hit {
 		if ship != nil && ship.IsSunk() {
-			g.LastMessage = "Hit! You sunk Captain Claude's " + ship.Name + "!"
+			g.LastMessage = "Hit! You sunk " + g.opponentName() + "'s " + ship.Name + "!"
 		} else {
 			g.LastMessage = "Hit!"
 		}
 
-		if g.ComputerBoard.AllShipsSunk() {
-			g.Phase = GameOverPhase
-			g.Winner = "Player"
-			g.LastMessage = "Victory! You sunk Captain Claude's fleet!"
+		if target.AllShipsSunk() {
+			g.declarePlayerVictory()
 			return true
 		}
 	} else {
 		g.LastMessage = "Miss!"
 	}
 
-	g.Phase = ComputerTurnPhase
-	g.ClaudeThinking = g.GetRandomThinkingMessage()
+	g.endPlayerTurn()
 	return true
 }
 
+// opponentName returns how the active player's opponent is referred to
+func (g *Game) opponentName() string {
+	if g.Mode == HotSeat {
+		return g.SideName(g.Active.Opponent())
+	}
+	if g.Mode == Network {
+		return "your opponent"
+	}
+	return "Captain Claude"
+}
+
 // GetRandomThinkingMessage returns a random thinking message for Claude
 func (g *Game) GetRandomThinkingMessage() string {
 	return thinkingMessages[g.Random.Intn(len(thinkingMessages))]
@@ -308,256 +451,180 @@ func (g *Game) GetRandomThinkingMessage() string {
 
 // ComputerAttack performs a computer attack on the player's board
 func (g *Game) ComputerAttack() {
-	if g.Phase != ComputerTurnPhase {
+	if g.Phase != ComputerTurnPhase || g.Mode == HotSeat || g.Mode == Network {
 		return
 	}
 
+	g.autoAttack(ComputerSide, g.ComputerStrategy)
+}
+
+// AutoPlayerAttack lets the computer take the player's turn in a game
+// between two computer players
+func (g *Game) AutoPlayerAttack() {
+	if g.Phase != PlayerTurnPhase || g.Mode != ComputerVsComputer {
+		return
+	}
+
+	g.autoAttack(PlayerSide, g.PlayerStrategy)
+}
+
+// strategy returns the registered strategy with the given name, falling back
+// to Easy if it is not registered
+func strategy(name string) Strategy {
+	if s, ok := LookupStrategy(name); ok {
+		return s
+	}
+	s, _ := LookupStrategy(Easy.String())
+	return s
+}
+
+// autoAttack fires a computer-controlled side's shot at the opposing board
+func (g *Game) autoAttack(shooter Side, strategyName string) {
 	if g.SalvoMode {
-		g.computerSalvoAttack()
+		g.computerSalvoAttack(shooter, strategyName)
 		return
 	}
 
-	var pos Position
+	target := g.Board(shooter.Opponent())
+	view := g.OpponentView(shooter)
+	pos := strategy(strategyName).NextTarget(view, g.Random)
 
-	// Choose attack strategy based on difficulty
-	switch g.Difficulty {
-	case Easy:
-		pos = g.easyAIAttack()
-	case Normal:
-		pos = g.normalAIAttack()
-	case Hard:
-		pos = g.hardAIAttack()
+	// Never trust a strategy to pick a legal cell
+	if !view.IsTargetable(pos) {
+		pos = easyStrategy{}.NextTarget(view, g.Random)
 	}
 
-	hit, ship := g.PlayerBoard.Attack(pos)
+	hit, ship := target.Attack(pos)
+	g.recordShot(shooter, pos, hit, ship)
 
 	if hit {
 		if ship != nil && ship.IsSunk() {
-			g.LastMessage = "Claude sunk your " + ship.Name + "!"
+			g.LastMessage = g.SideName(shooter) + " sunk " + g.fleetOwner(shooter.Opponent()) + " " + ship.Name + "!"
 		} else {
-			g.LastMessage = "Claude hit your ship!"
-		}
-
-		if g.PlayerBoard.AllShipsSunk() {
-			g.Phase = GameOverPhase
-			g.Winner = "Claude"
-			g.LastMessage = "Defeat! All your ships were sunk!"
-			return
+			g.LastMessage = g.SideName(shooter) + " hit " + g.fleetOwner(shooter.Opponent()) + " ship!"
 		}
 	} else {
-		g.LastMessage = "Claude missed!"
+		g.LastMessage = g.SideName(shooter) + " missed!"
 	}
 
-	g.Phase = PlayerTurnPhase
+	g.endAutoTurn(shooter)
 }
 
 // computerSalvoAttack performs multiple attacks for salvo mode
-func (g *Game) computerSalvoAttack() {
-	numShots := g.GetRemainingShips(false) // Computer's remaining ships
-	hits := 0
-	misses := 0
-	sunkShips := []string{}
-
-	for i := 0; i < numShots; i++ {
-		var pos Position
-
-		// Choose attack strategy based on difficulty
-		switch g.Difficulty {
-		case Easy:
-			pos = g.easyAIAttack()
-		case Normal:
-			pos = g.normalAIAttack()
-		case Hard:
-			pos = g.hardAIAttack()
-		}
-
-		hit, ship := g.PlayerBoard.Attack(pos)
+func (g *Game) computerSalvoAttack(shooter Side, strategyName string) {
+	target := g.Board(shooter.Opponent())
+	result := SalvoResult{Shooter: shooter}
+
+	// A chain is a run of single-shot salvos that goes on for as long as it hits
+	for {
+		salvo := strategy(strategyName).NextSalvo(g.OpponentView(shooter), g.SalvoShots(shooter), g.Random)
+		fired := 0
+		for _, pos := range salvo {
+			// Stop once the fleet is gone, the rest of the salvo has nothing left to hit
+			if target.AllShipsSunk() {
+				break
+			}
 
-		if hit {
-			hits++
-			if ship != nil && ship.IsSunk() {
-				sunkShips = append(sunkShips, ship.Name)
+			// Skip cells that cannot be fired at, in case a strategy repeats itself
+			if !g.OpponentView(shooter).IsTargetable(pos) {
+				continue
 			}
-		} else {
-			misses++
-		}
-	}
 
-	// Build message
-	msg := "Claude's salvo: "
-	if hits > 0 {
-		msg += "Hits: " + string(rune('0'+hits))
-	}
-	if misses > 0 {
-		if hits > 0 {
-			msg += " | "
+			hit, ship := target.Attack(pos)
+			result.Shots = append(result.Shots, g.recordShot(shooter, pos, hit, ship))
+			fired++
 		}
-		msg += "Misses: " + string(rune('0'+misses))
-	}
-	if len(sunkShips) > 0 {
-		msg += " | Sunk: "
-		for i, name := range sunkShips {
-			if i > 0 {
-				msg += ", "
-			}
-			msg += name
+
+		if g.SalvoRule.Kind != ChainShots || fired == 0 || result.Shots[len(result.Shots)-1].Result == ShotMiss || target.AllShipsSunk() {
+			break
 		}
 	}
 
-	g.LastMessage = msg
+	g.LastMessage = g.SideName(shooter) + "'s salvo: " + result.Summary()
+	g.endAutoTurn(shooter)
+}
 
-	if g.PlayerBoard.AllShipsSunk() {
+// endAutoTurn ends a computer-controlled side's turn, either declaring it the
+// winner or passing play to the other side
+func (g *Game) endAutoTurn(shooter Side) {
+	if g.Board(shooter.Opponent()).AllShipsSunk() {
 		g.Phase = GameOverPhase
-		g.Winner = "Claude"
-		g.LastMessage = "Defeat! All your ships were sunk!"
+		if shooter == ComputerSide {
+			g.Winner = "Claude"
+			g.LastMessage = "Defeat! All your ships were sunk!"
+		} else {
+			g.Winner = "Player"
+			g.LastMessage = "Victory! You sunk Captain Claude's fleet!"
+		}
 		return
 	}
 
-	g.Phase = PlayerTurnPhase
+	if shooter == ComputerSide {
+		g.Phase = PlayerTurnPhase
+		g.Turn++
+	} else {
+		g.Phase = ComputerTurnPhase
+		g.ClaudeThinking = g.GetRandomThinkingMessage()
+	}
 }
 
-// easyAIAttack implements easy difficulty - random attacks
-func (g *Game) easyAIAttack() Position {
-	var pos Position
-	found := false
-
-	for !found {
-		row := g.Random.Intn(g.BoardSize)
-		col := g.Random.Intn(g.BoardSize)
-		pos = Position{Row: row, Col: col}
+// OpponentView returns what a side knows of its opponent's board. When salvo
+// results only announce the number of hits, that is less than the board shows.
+func (g *Game) OpponentView(shooter Side) FogView {
+	target := g.Board(shooter.Opponent())
+	if g.SalvoMode && g.HitCountsOnly {
+		return newCountedFogView(target, &g.Shots, shooter)
+	}
+	return NewFogView(target)
+}
 
-		cell := g.PlayerBoard.GetCell(pos)
-		if cell != Hit && cell != Miss {
-			found = true
-		}
+// Board returns the board holding a side's fleet
+func (g *Game) Board(side Side) *Board {
+	if side == PlayerSide {
+		return g.PlayerBoard
 	}
+	return g.ComputerBoard
+}
 
-	return pos
-}
-
-// normalAIAttack implements normal difficulty - hunts around hits
-func (g *Game) normalAIAttack() Position {
-	// First, look for existing hits to follow up on
-	for row := 0; row < g.BoardSize; row++ {
-		for col := 0; col < g.BoardSize; col++ {
-			if g.PlayerBoard.Grid[row][col] == Hit {
-				// Found a hit, try adjacent cells
-				adjacents := []Position{
-					{Row: row - 1, Col: col},
-					{Row: row + 1, Col: col},
-					{Row: row, Col: col - 1},
-					{Row: row, Col: col + 1},
-				}
-
-				// Shuffle adjacents for variety
-				for i := range adjacents {
-					j := g.Random.Intn(i + 1)
-					adjacents[i], adjacents[j] = adjacents[j], adjacents[i]
-				}
-
-				for _, adj := range adjacents {
-					if g.PlayerBoard.IsValidPosition(adj) {
-						cell := g.PlayerBoard.GetCell(adj)
-						if cell != Hit && cell != Miss {
-							return adj
-						}
-					}
-				}
-			}
+// SideName returns how a side is referred to in messages
+func (g *Game) SideName(side Side) string {
+	if g.Mode == HotSeat {
+		if side == PlayerSide {
+			return "Player 1"
 		}
+		return "Player 2"
 	}
-
-	// No hits to follow up on, attack randomly
-	return g.easyAIAttack()
-}
-
-// hardAIAttack implements hard difficulty - smart pattern hunting and direction following
-func (g *Game) hardAIAttack() Position {
-	// Look for hits in a line (ship orientation detected)
-	for row := 0; row < g.BoardSize; row++ {
-		for col := 0; col < g.BoardSize; col++ {
-			if g.PlayerBoard.Grid[row][col] == Hit {
-				// Check horizontal line
-				if col+1 < g.BoardSize && g.PlayerBoard.Grid[row][col+1] == Hit {
-					// Found horizontal ship, extend in both directions
-					// Try right first
-					if col+2 < g.BoardSize {
-						adj := Position{Row: row, Col: col + 2}
-						cell := g.PlayerBoard.GetCell(adj)
-						if cell != Hit && cell != Miss {
-							return adj
-						}
-					}
-					// Try left
-					if col-1 >= 0 {
-						adj := Position{Row: row, Col: col - 1}
-						cell := g.PlayerBoard.GetCell(adj)
-						if cell != Hit && cell != Miss {
-							return adj
-						}
-					}
-				}
-
-				// Check vertical line
-				if row+1 < g.BoardSize && g.PlayerBoard.Grid[row+1][col] == Hit {
-					// Found vertical ship, extend in both directions
-					// Try down first
-					if row+2 < g.BoardSize {
-						adj := Position{Row: row + 2, Col: col}
-						cell := g.PlayerBoard.GetCell(adj)
-						if cell != Hit && cell != Miss {
-							return adj
-						}
-					}
-					// Try up
-					if row-1 >= 0 {
-						adj := Position{Row: row - 1, Col: col}
-						cell := g.PlayerBoard.GetCell(adj)
-						if cell != Hit && cell != Miss {
-							return adj
-						}
-					}
-				}
-			}
+	if side == ComputerSide {
+		if g.Mode == Network {
+			return "Opponent"
 		}
+		return "Claude"
 	}
+	return "Player"
+}
 
-	// No line detected, use normal mode's adjacent hunting
-	for row := 0; row < g.BoardSize; row++ {
-		for col := 0; col < g.BoardSize; col++ {
-			if g.PlayerBoard.Grid[row][col] == Hit {
-				adjacents := []Position{
-					{Row: row - 1, Col: col},
-					{Row: row + 1, Col: col},
-					{Row: row, Col: col - 1},
-					{Row: row, Col: col + 1},
-				}
-
-				for _, adj := range adjacents {
-					if g.PlayerBoard.IsValidPosition(adj) {
-						cell := g.PlayerBoard.GetCell(adj)
-						if cell != Hit && cell != Miss {
-							return adj
-						}
-					}
-				}
-			}
-		}
+// fleetOwner returns the possessive used for a side's fleet in messages
+func (g *Game) fleetOwner(side Side) string {
+	if side == PlayerSide && g.Mode == VsComputer {
+		return "your"
 	}
+	return g.SideName(side) + "'s"
+}
 
-	// No hits to follow, use checkerboard pattern for efficient hunting
-	for row := 0; row < g.BoardSize; row++ {
-		for col := 0; col < g.BoardSize; col++ {
-			if (row+col)%2 == 0 { // Checkerboard pattern
-				pos := Position{Row: row, Col: col}
-				cell := g.PlayerBoard.GetCell(pos)
-				if cell != Hit && cell != Miss {
-					return pos
-				}
+// recordShot adds an attack and its outcome to the shot ledger, and returns
+// the record
+func (g *Game) recordShot(shooter Side, pos Position, hit bool, ship *Ship) ShotRecord {
+	result := ShotMiss
+	name := ""
+	if hit {
+		result = ShotHit
+		if ship != nil {
+			name = ship.Name
+			if ship.IsSunk() {
+				result = ShotSunk
 			}
 		}
 	}
-
-	// Checkerboard exhausted, fill in remaining cells
-	return g.easyAIAttack()
+	g.Shots.Record(shooter, pos, result, name, g.Turn)
+	return g.Shots.Shots[len(g.Shots.Shots)-1]
 }
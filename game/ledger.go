@@ -0,0 +1,118 @@
+package game
+
+// Side identifies one of the two fleets in a game
+type Side int
+
+const (
+	PlayerSide Side = iota
+	ComputerSide
+)
+
+// Opponent returns the other side
+func (s Side) Opponent() Side {
+	if s == PlayerSide {
+		return ComputerSide
+	}
+	return PlayerSide
+}
+
+// ShotResult represents the outcome of a single shot
+type ShotResult int
+
+const (
+	ShotMiss ShotResult = iota
+	ShotHit
+	ShotSunk
+)
+
+// ShotRecord is a single entry in the shot ledger
+type ShotRecord struct {
+	Shooter Side       `json:"shooter"`
+	Target  Position   `json:"target"`
+	Result  ShotResult `json:"result"`
+	Ship    string     `json:"ship,omitempty"` // Name of the ship hit or sunk, empty on a miss
+	Turn    int        `json:"turn"`
+}
+
+// ShotLedger keeps an ordered record of every attack made by both sides
+type ShotLedger struct {
+	Shots []ShotRecord
+}
+
+// Record appends a shot to the ledger
+func (l *ShotLedger) Record(shooter Side, target Position, result ShotResult, ship string, turn int) {
+	l.Shots = append(l.Shots, ShotRecord{
+		Shooter: shooter,
+		Target:  target,
+		Result:  result,
+		Ship:    ship,
+		Turn:    turn,
+	})
+}
+
+// ShotsFired returns the number of shots fired by a side
+func (l *ShotLedger) ShotsFired(shooter Side) int {
+	count := 0
+	for _, shot := range l.Shots {
+		if shot.Shooter == shooter {
+			count++
+		}
+	}
+	return count
+}
+
+// Hits returns the number of shots by a side that hit a ship
+func (l *ShotLedger) Hits(shooter Side) int {
+	count := 0
+	for _, shot := range l.Shots {
+		if shot.Shooter == shooter && shot.Result != ShotMiss {
+			count++
+		}
+	}
+	return count
+}
+
+// Accuracy returns the fraction of a side's shots that hit, or 0 if it never fired
+func (l *ShotLedger) Accuracy(shooter Side) float64 {
+	fired := l.ShotsFired(shooter)
+	if fired == 0 {
+		return 0
+	}
+	return float64(l.Hits(shooter)) / float64(fired)
+}
+
+// HitsBeforeSink returns how many shots a side fired from its first hit on the
+// named ship up to and including the shot that sank it. It returns -1 if the
+// ship was never sunk by that side.
+func (l *ShotLedger) HitsBeforeSink(shooter Side, ship string) int {
+	count := 0
+	for _, shot := range l.Shots {
+		if shot.Shooter != shooter {
+			continue
+		}
+		if count == 0 && shot.Ship != ship {
+			continue
+		}
+		count++
+		if shot.Ship == ship && shot.Result == ShotSunk {
+			return count
+		}
+	}
+	return -1
+}
+
+// Volleys returns the shots grouped into turns, in order. Each side's turn is
+// a single shot, or all the shots of a salvo.
+func (l *ShotLedger) Volleys() [][]ShotRecord {
+	var volleys [][]ShotRecord
+	for i, shot := range l.Shots {
+		// Consecutive shots by one side in one turn are a single salvo
+		if i > 0 && shot.Shooter == l.Shots[i-1].Shooter && shot.Turn == l.Shots[i-1].Turn {
+			last := len(volleys) - 1
+			volleys[last] = append(volleys[last], shot)
+		} else {
+			volleys = append(volleys, []ShotRecord{shot})
+		}
+	}
+	return volleys
+}
@@ -0,0 +1,185 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotYourTurn is returned when a remote opponent acts out of turn
+var ErrNotYourTurn = errors.New("opponent acted out of turn")
+
+// StartNetworkBattle begins the battle once both players have placed their
+// fleets. The host fires first. Once the battle has begun it does nothing.
+func (g *Game) StartNetworkBattle(firstToFire bool) {
+	if g.Mode != Network || g.Phase != WaitingPhase || g.Turn != 0 || g.remoteShot != nil {
+		return
+	}
+
+	g.Turn = 1
+	if firstToFire {
+		g.Phase = PlayerTurnPhase
+		g.LastMessage = "Both fleets are ready! Your turn to attack!"
+		return
+	}
+	g.Phase = ComputerTurnPhase
+	g.LastMessage = "Both fleets are ready! Your opponent fires first."
+}
+
+// fireRemoteShot checks a shot at the remote opponent and waits for its result
+func (g *Game) fireRemoteShot(pos Position) bool {
+	if !g.ComputerBoard.IsValidPosition(pos) {
+		g.LastMessage = "Invalid position!"
+		return false
+	}
+
+	cell := g.ComputerBoard.GetCell(pos)
+	if cell == Hit || cell == Miss {
+		g.LastMessage = "You already attacked that position!"
+		return false
+	}
+
+	g.Phase = WaitingPhase
+	g.remoteShot = &pos
+	g.LastMessage = "Firing at " + pos.String() + "..."
+	return true
+}
+
+// ApplyShotResult records the result the remote opponent reported for the
+// local player's last shot. sunk holds the revealed ship if it was sunk.
+func (g *Game) ApplyShotResult(pos Position, result ShotResult, sunk *Ship) error {
+	// Waiting for the opponent's fleet is not waiting for a result
+	if g.Mode != Network || g.Phase != WaitingPhase || g.remoteShot == nil {
+		return ErrNotYourTurn
+	}
+	if pos != *g.remoteShot {
+		return fmt.Errorf("result for %s but %s was fired at", pos, *g.remoteShot)
+	}
+
+	name := ""
+	switch result {
+	case ShotMiss:
+		g.ComputerBoard.Grid[pos.Row][pos.Col] = Miss
+		g.LastMessage = "Miss!"
+	case ShotHit:
+		g.ComputerBoard.Grid[pos.Row][pos.Col] = Hit
+		g.LastMessage = "Hit!"
+	case ShotSunk:
+		if sunk == nil || !sunk.covers(pos) {
+			return fmt.Errorf("sunk ship does not cover %s", pos)
+		}
+		if !g.expectsSunkShip(sunk) {
+			return fmt.Errorf("%s of length %d is not in the fleet or was already sunk", sunk.Name, sunk.Length)
+		}
+		for _, p := range sunk.Positions {
+			if !g.ComputerBoard.IsValidPosition(p) {
+				return fmt.Errorf("sunk ship at %s is off the board", p)
+			}
+			g.ComputerBoard.Grid[p.Row][p.Col] = Hit
+		}
+		g.ComputerBoard.Ships = append(g.ComputerBoard.Ships, sunk)
+		name = sunk.Name
+		g.LastMessage = "Hit! You sunk " + g.opponentName() + "'s " + sunk.Name + "!"
+	default:
+		return fmt.Errorf("unknown shot result %d", result)
+	}
+	g.Shots.Record(PlayerSide, pos, result, name, g.Turn)
+	g.remoteShot = nil
+
+	// Ships only appear on the tracking board once sunk, so the fleet is gone
+	// when every ship has been revealed
+	if len(g.ComputerBoard.Ships) == len(g.Fleet.Ships) {
+		g.declarePlayerVictory()
+		return nil
+	}
+
+	g.nextNetworkTurn(PlayerSide)
+	g.Phase = ComputerTurnPhase
+	return nil
+}
+
+// expectsSunkShip returns true if ship matches a ship of the fleet that has
+// not been reported sunk yet
+func (g *Game) expectsSunkShip(ship *Ship) bool {
+	spec, ok := g.Fleet.Spec(ship.Name)
+	if !ok || spec.Length != ship.Length {
+		return false
+	}
+	for _, sunk := range g.ComputerBoard.Ships {
+		if sunk.Name == ship.Name {
+			return false
+		}
+	}
+	return true
+}
+
+// ReceiveShot resolves a shot fired by the remote opponent at the local fleet
+// and returns the result to report back, with the ship if it was sunk
+func (g *Game) ReceiveShot(pos Position) (ShotResult, *Ship, error) {
+	if g.Mode != Network || g.Phase != ComputerTurnPhase {
+		return ShotMiss, nil, ErrNotYourTurn
+	}
+	if !g.PlayerBoard.IsValidPosition(pos) {
+		return ShotMiss, nil, fmt.Errorf("shot at %s is off the board", pos)
+	}
+	cell := g.PlayerBoard.GetCell(pos)
+	if cell == Hit || cell == Miss {
+		return ShotMiss, nil, fmt.Errorf("%s was already attacked", pos)
+	}
+
+	hit, ship := g.PlayerBoard.Attack(pos)
+	g.recordShot(ComputerSide, pos, hit, ship)
+	result := g.Shots.Shots[len(g.Shots.Shots)-1].Result
+
+	switch result {
+	case ShotSunk:
+		g.LastMessage = "Opponent sunk your " + ship.Name + "!"
+	case ShotHit:
+		g.LastMessage = "Opponent hit your ship at " + pos.String() + "!"
+	default:
+		g.LastMessage = "Opponent missed at " + pos.String() + "!"
+	}
+
+	if result != ShotSunk {
+		ship = nil
+	}
+
+	if g.PlayerBoard.AllShipsSunk() {
+		g.Phase = GameOverPhase
+		g.Winner = g.SideName(ComputerSide)
+		g.LastMessage = "Defeat! All your ships were sunk!"
+		return result, ship, nil
+	}
+
+	g.nextNetworkTurn(ComputerSide)
+	g.Phase = PlayerTurnPhase
+	return result, ship, nil
+}
+
+// nextNetworkTurn advances the turn number once the side that fires second
+// has taken its shot
+func (g *Game) nextNetworkTurn(shooter Side) {
+	if g.Shots.Shots[0].Shooter != shooter {
+		g.Turn++
+	}
+}
+
+// Resign ends a network game with the remote opponent as the winner
+func (g *Game) Resign() {
+	g.Phase = GameOverPhase
+	g.Winner = g.SideName(ComputerSide)
+	g.LastMessage = "You resigned."
+}
+
+// OpponentResigned ends a network game with the local player as the winner
+func (g *Game) OpponentResigned() {
+	g.Phase = GameOverPhase
+	g.Winner = g.SideName(PlayerSide)
+	g.LastMessage = "Your opponent resigned!"
+}
+
+// Abandon ends a game that cannot continue, without a winner
+func (g *Game) Abandon(reason string) {
+	g.Phase = GameOverPhase
+	g.Winner = ""
+	g.LastMessage = reason
+}
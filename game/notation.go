@@ -0,0 +1,322 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// NotationHeader is the first line of a game written in game notation
+const NotationHeader = "[Battleship]"
+
+// Names of the game modes and orientations in game notation
+var (
+	notationModes = map[GameMode]string{
+		VsComputer:         "vs computer",
+		ComputerVsComputer: "computer vs computer",
+		HotSeat:            "hot seat",
+	}
+	notationOrientations = map[Orientation]string{
+		Horizontal: "H",
+		Vertical:   "V",
+	}
+)
+
+// notationSide is how a side is written in game notation: P for the player
+// side, or Player 1 in hot seat, and C for the other
+func notationSide(side Side) string {
+	if side == PlayerSide {
+		return "P"
+	}
+	return "C"
+}
+
+// WriteNotation writes the replay in game notation, a compact text form for
+// sharing games. A header of "Key: value" lines gives the settings, seed,
+// winner and both fleets, followed by a blank line and one line per move:
+//
+//  12. P: B7 hit
+//  12. C: E3 miss
+//  13. P: B8 sunk Destroyer
+//
+// A salvo is a single move listing every shot, e.g. "4. P: C4 hit, C5 miss".
+func (r *Replay) WriteNotation(w io.Writer) error {
+	s := r.Settings
+	rules := "touching"
+	if s.Rules.NoTouch {
+		rules = "no touching"
+	}
+	firing := "single"
+	if s.SalvoMode {
+		firing = "salvo"
+	}
+	if s.SalvoMode && s.HitCountsOnly {
+		firing = "salvo, hit counts only"
+	}
+	ships := make([]string, len(s.Fleet.Ships))
+	for i, spec := range s.Fleet.Ships {
+		ships[i] = fmt.Sprintf("%s %d", spec.Name, spec.Length)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(NotationHeader + "\n")
+	fmt.Fprintf(&sb, "Size: %dx%d\n", s.Rows, s.Cols)
+	fmt.Fprintf(&sb, "Fleet: %s\n", s.Fleet.Name)
+	fmt.Fprintf(&sb, "Ships: %s\n", strings.Join(ships, ", "))
+	fmt.Fprintf(&sb, "Rules: %s\n", rules)
+	fmt.Fprintf(&sb, "Firing: %s\n", firing)
+	if s.SalvoMode {
+		fmt.Fprintf(&sb, "Salvo shots: %s\n", strings.ToLower(s.SalvoRule.String()))
+	}
+	fmt.Fprintf(&sb, "Mode: %s\n", notationModes[s.Mode])
+	fmt.Fprintf(&sb, "Strategy: %s\n", s.Strategy)
+	if s.Mode == ComputerVsComputer {
+		fmt.Fprintf(&sb, "Player strategy: %s\n", s.PlayerStrategy)
+	}
+	fmt.Fprintf(&sb, "Seed: %d\n", r.Seed)
+	fmt.Fprintf(&sb, "Winner: %s\n", r.Winner)
+	fmt.Fprintf(&sb, "P fleet: %s\n", notationFleet(r.PlayerFleet))
+	fmt.Fprintf(&sb, "C fleet: %s\n", notationFleet(r.ComputerFleet))
+	sb.WriteString("\n")
+
+	for _, volley := range r.volleys {
+		shots := make([]string, len(volley))
+		for i, shot := range volley {
+			shots[i] = notationShot(shot)
+		}
+		fmt.Fprintf(&sb, "%d. %s: %s\n", volley[0].Turn, notationSide(volley[0].Shooter), strings.Join(shots, ", "))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// notationShot writes a shot and its result, e.g. "B7 hit" or "B8 sunk Destroyer"
+func notationShot(shot ShotRecord) string {
+	switch shot.Result {
+	case ShotHit:
+		return shot.Target.String() + " hit"
+	case ShotSunk:
+		return shot.Target.String() + " sunk " + shot.Ship
+	}
+	return shot.Target.String() + " miss"
+}
+
+// notationFleet writes where each ship was placed, e.g. "Carrier A1 H"
+func notationFleet(placements []ShipPlacement) string {
+	ships := make([]string, len(placements))
+	for i, p := range placements {
+		ships[i] = fmt.Sprintf("%s %s %s", p.Ship, p.Position, notationOrientations[p.Orientation])
+	}
+	return strings.Join(ships, ", ")
+}
+
+// ParseNotation reads a game written by WriteNotation. Every move is fired
+// at the fleets given in the header and must be legal and have the result
+// it claims.
+func ParseNotation(rd io.Reader) (*Replay, error) {
+	scanner := bufio.NewScanner(rd)
+	line := 1
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != NotationHeader {
+		return nil, fmt.Errorf("game notation must start with %s", NotationHeader)
+	}
+
+	// Header, up to the first blank line
+	header := map[string]string{}
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			break
+		}
+		key, value, ok := strings.Cut(text, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"Key: value\", got %q", line, text)
+		}
+		header[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+
+	r := &Replay{Version: ReplayVersion, Winner: header["winner"]}
+	if err := parseNotationHeader(header, r); err != nil {
+		return nil, err
+	}
+
+	// Moves, fired as they are read so a mistake is reported on its line
+	g, err := r.setup()
+	if err != nil {
+		return nil, fmt.Errorf("the fleets in the header cannot be placed: %w", err)
+	}
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if err := g.parseNotationMove(text); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	r.Shots = g.Shots.Shots
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// parseNotationHeader fills in a replay's settings, seed and fleets from the
+// header of a game in game notation
+func parseNotationHeader(header map[string]string, r *Replay) error {
+	for _, key := range []string{"size", "fleet", "ships", "rules", "firing", "mode", "strategy", "seed", "winner", "p fleet", "c fleet"} {
+		if _, ok := header[key]; !ok {
+			return fmt.Errorf("game notation has no %q header", key)
+		}
+	}
+
+	s := &r.Settings
+	var err error
+	if s.Rows, s.Cols, err = ParseBoardSize(header["size"]); err != nil {
+		return err
+	}
+
+	s.Fleet.Name = header["fleet"]
+	for _, ship := range strings.Split(header["ships"], ", ") {
+		i := strings.LastIndex(ship, " ")
+		length, err := strconv.Atoi(ship[i+1:])
+		if i < 0 || err != nil {
+			return fmt.Errorf("invalid ship %q, expected a name and a length", ship)
+		}
+		s.Fleet.Ships = append(s.Fleet.Ships, ShipSpec{Name: ship[:i], Length: length})
+	}
+	if err := s.Fleet.Validate(); err != nil {
+		return err
+	}
+
+	switch header["rules"] {
+	case "touching":
+	case "no touching":
+		s.Rules.NoTouch = true
+	default:
+		return fmt.Errorf("unknown rules %q", header["rules"])
+	}
+
+	switch header["firing"] {
+	case "single":
+	case "salvo":
+		s.SalvoMode = true
+	case "salvo, hit counts only":
+		s.SalvoMode = true
+		s.HitCountsOnly = true
+	default:
+		return fmt.Errorf("unknown firing mode %q", header["firing"])
+	}
+	if text, ok := header["salvo shots"]; ok {
+		if s.SalvoRule, err = ParseSalvoRule(text); err != nil {
+			return err
+		}
+	}
+
+	mode, ok := lookupNotation(notationModes, header["mode"])
+	if !ok {
+		return fmt.Errorf("unknown mode %q", header["mode"])
+	}
+	s.Mode = mode
+	s.Strategy = header["strategy"]
+	s.PlayerStrategy = header["player strategy"]
+
+	if r.Seed, err = strconv.ParseInt(header["seed"], 10, 64); err != nil {
+		return fmt.Errorf("invalid seed %q", header["seed"])
+	}
+	if err := CheckBoardSize(s.Rows, s.Cols, s.Fleet, s.Rules); err != nil {
+		return err
+	}
+
+	if r.PlayerFleet, err = parseNotationFleet(header["p fleet"]); err != nil {
+		return err
+	}
+	r.ComputerFleet, err = parseNotationFleet(header["c fleet"])
+	return err
+}
+
+// parseNotationFleet reads where each ship was placed, e.g. "Carrier A1 H"
+func parseNotationFleet(text string) ([]ShipPlacement, error) {
+	var placements []ShipPlacement
+	for _, ship := range strings.Split(text, ", ") {
+		fields := strings.Fields(ship)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid placement %q, expected a ship, a position and H or V", ship)
+		}
+
+		pos, err := ParsePosition(fields[len(fields)-2])
+		if err != nil {
+			return nil, err
+		}
+		orientation, ok := lookupNotation(notationOrientations, fields[len(fields)-1])
+		if !ok {
+			return nil, fmt.Errorf("invalid orientation %q, expected H or V", fields[len(fields)-1])
+		}
+		placements = append(placements, ShipPlacement{
+			Ship:        strings.Join(fields[:len(fields)-2], " "),
+			Position:    pos,
+			Orientation: orientation,
+		})
+	}
+	return placements, nil
+}
+
+// lookupNotation returns the key written as name in game notation
+func lookupNotation[K comparable](names map[K]string, name string) (K, bool) {
+	for key, n := range names {
+		if n == name {
+			return key, true
+		}
+	}
+	var zero K
+	return zero, false
+}
+
+// parseNotationMove fires the shots of one move, e.g. "12. P: B7 hit",
+// checking that it comes next, that each shot is legal and has the claimed
+// result
+func (g *Game) parseNotationMove(text string) error {
+	turnText, rest, ok := strings.Cut(text, ". ")
+	turn, err := strconv.Atoi(turnText)
+	if !ok || err != nil || turn < 1 {
+		return fmt.Errorf("expected a move like \"12. P: B7 hit\", got %q", text)
+	}
+	sideText, shots, ok := strings.Cut(rest, ": ")
+	if !ok || (sideText != "P" && sideText != "C") {
+		return fmt.Errorf("expected P or C to fire, got %q", rest)
+	}
+	shooter := PlayerSide
+	if sideText == "C" {
+		shooter = ComputerSide
+	}
+
+	for _, shotText := range strings.Split(shots, ", ") {
+		fields := strings.Fields(shotText)
+		if len(fields) < 2 {
+			return fmt.Errorf("expected a shot like \"B7 hit\", got %q", shotText)
+		}
+		pos, err := ParsePosition(fields[0])
+		if err != nil {
+			return err
+		}
+		if err := g.checkShot(shooter, turn, pos); err != nil {
+			return err
+		}
+
+		g.Turn = turn
+		hit, ship := g.Board(shooter.Opponent()).Attack(pos)
+		actual := notationShot(g.recordShot(shooter, pos, hit, ship))
+		if claimed := strings.Join(fields, " "); claimed != actual {
+			return fmt.Errorf("%q does not match the fleets, it was %q", claimed, actual)
+		}
+	}
+	return nil
+}
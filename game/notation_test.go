@@ -0,0 +1,272 @@
+package game
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// playComputers plays a computer-vs-computer game to the end
+func playComputers(t *testing.T, settings Settings, seed int64) *Game {
+	t.Helper()
+	settings.Mode = ComputerVsComputer
+	settings.PlayerStrategy = Hard.String()
+	g, err := NewGameWithSeed(settings, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for g.Phase != GameOverPhase {
+		if g.Phase == PlayerTurnPhase {
+			g.AutoPlayerAttack()
+		} else {
+			g.ComputerAttack()
+		}
+	}
+	return g
+}
+
+// salvoSettings returns the default settings with salvos fired by rule
+func salvoSettings(rule SalvoRule, hitCountsOnly bool) Settings {
+	s := DefaultSettings()
+	s.SalvoMode = true
+	s.SalvoRule = rule
+	s.HitCountsOnly = hitCountsOnly
+	return s
+}
+
+// notation writes a finished game in game notation
+func notation(t *testing.T, g *Game) string {
+	t.Helper()
+	r, err := NewReplay(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := r.WriteNotation(&sb); err != nil {
+		t.Fatal(err)
+	}
+	return sb.String()
+}
+
+// editMoves returns a game in notation with its move lines changed by edit
+func editMoves(text string, edit func(moves []string) []string) string {
+	header, moves, _ := strings.Cut(text, "\n\n")
+	return header + "\n\n" + strings.Join(edit(strings.Split(strings.TrimSpace(moves), "\n")), "\n") + "\n"
+}
+
+// untouchedCell returns a cell of a board that holds no ship and was never
+// fired at
+func untouchedCell(t *testing.T, b *Board) Position {
+	t.Helper()
+	for row := 0; row < b.Rows; row++ {
+		for col := 0; col < b.Cols; col++ {
+			if b.Grid[row][col] == Empty {
+				return Position{Row: row, Col: col}
+			}
+		}
+	}
+	t.Fatal("every cell was fired at")
+	return Position{}
+}
+
+func TestNotationRoundTrip(t *testing.T) {
+	noTouch := DefaultSettings()
+	noTouch.Rules.NoTouch = true
+	noTouchGame, err := NewGameWithSeed(noTouch, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sunk := noTouchGame.ComputerBoard.Ships[0].Positions
+	ruledOut := append(append([]Position{}, sunk...), neighbours(sunk[0], noTouch.Rows, noTouch.Cols)...)
+
+	tests := []struct {
+		name string
+		game *Game
+	}{
+		{"single shot", playComputers(t, DefaultSettings(), 1)},
+		{"single shot against the computer", playAgainstComputer(t, DefaultSettings(), 2, nil)},
+		{"shots next to a sunk ship", playAgainstComputer(t, noTouch, 3, ruledOut)},
+		{"salvo", playComputers(t, salvoSettings(SalvoRule{}, false), 4)},
+		{"fixed salvo", playComputers(t, salvoSettings(SalvoRule{Kind: FixedShots, Shots: 3}, false), 5)},
+		{"largest ship salvo", playComputers(t, salvoSettings(SalvoRule{Kind: LargestShip}, false), 6)},
+		{"chain", playComputers(t, salvoSettings(SalvoRule{Kind: ChainShots}, false), 7)},
+		{"hit counts only", playComputers(t, salvoSettings(SalvoRule{}, true), 8)},
+		{"player salvo", playAgainstComputer(t, salvoSettings(SalvoRule{}, false), 9, nil)},
+	}
+	for _, tt := range tests {
+		original, err := NewReplay(tt.game)
+		if err != nil {
+			t.Errorf("%s: NewReplay() = %v", tt.name, err)
+			continue
+		}
+		text := notation(t, tt.game)
+
+		parsed, err := ParseNotation(strings.NewReader(text))
+		if err != nil {
+			t.Errorf("%s: ParseNotation() = %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(parsed.Settings, original.Settings) {
+			t.Errorf("%s: settings = %+v, want %+v", tt.name, parsed.Settings, original.Settings)
+		}
+		if parsed.Seed != original.Seed || parsed.Winner != original.Winner {
+			t.Errorf("%s: seed and winner = %d, %q, want %d, %q", tt.name, parsed.Seed, parsed.Winner, original.Seed, original.Winner)
+		}
+		if !reflect.DeepEqual(parsed.PlayerFleet, original.PlayerFleet) || !reflect.DeepEqual(parsed.ComputerFleet, original.ComputerFleet) {
+			t.Errorf("%s: fleets differ", tt.name)
+		}
+		if !reflect.DeepEqual(parsed.Shots, original.Shots) {
+			t.Errorf("%s: shots differ", tt.name)
+		}
+
+		var again bytes.Buffer
+		if err := parsed.WriteNotation(&again); err != nil || again.String() != text {
+			t.Errorf("%s: writing the parsed game again gave a different text, %v", tt.name, err)
+		}
+	}
+}
+
+func TestParseNotationRejectsIllegalMoves(t *testing.T) {
+	single := playComputers(t, DefaultSettings(), 1)
+	singleText := notation(t, single)
+	spare := untouchedCell(t, single.ComputerBoard)
+
+	fixed := playComputers(t, salvoSettings(SalvoRule{Kind: FixedShots, Shots: 3}, false), 5)
+	fixedText := notation(t, fixed)
+	fixedSpare := untouchedCell(t, fixed.ComputerBoard)
+
+	chain := playComputers(t, salvoSettings(SalvoRule{Kind: ChainShots}, false), 7)
+	chainText := notation(t, chain)
+	chainSpare := untouchedCell(t, chain.ComputerBoard)
+
+	// The first move of the chain game that hit before it missed
+	chainRun := -1
+	for i, move := range strings.Split(strings.TrimSpace(strings.SplitN(chainText, "\n\n", 2)[1]), "\n") {
+		if strings.Contains(move, ", ") && strings.HasSuffix(move, " miss") {
+			chainRun = i
+			break
+		}
+	}
+	if chainRun < 0 {
+		t.Fatal("the chain game never hit more than once in a row")
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string // Part of the error
+	}{
+		{
+			"misreported result",
+			editMoves(singleText, func(moves []string) []string {
+				for i, move := range moves {
+					if strings.HasSuffix(move, " miss") {
+						moves[i] = strings.TrimSuffix(move, " miss") + " hit"
+						break
+					}
+				}
+				return moves
+			}),
+			"does not match the fleets",
+		},
+		{
+			"misreported ship",
+			editMoves(singleText, func(moves []string) []string {
+				for i, move := range moves {
+					if strings.Contains(move, " sunk ") {
+						moves[i] = move[:strings.Index(move, " sunk ")] + " sunk Nothing"
+						break
+					}
+				}
+				return moves
+			}),
+			"does not match the fleets",
+		},
+		{
+			"repeated shot",
+			editMoves(singleText, func(moves []string) []string {
+				first := strings.Fields(moves[0])[2]
+				fields := strings.Fields(moves[2])
+				moves[2] = strings.Join(append([]string{fields[0], fields[1], first}, fields[3:]...), " ")
+				return moves
+			}),
+			"cannot be fired at",
+		},
+		{
+			"off the board",
+			editMoves(singleText, func(moves []string) []string {
+				moves[0] = "1. P: K11 miss"
+				return moves
+			}),
+			"K11 cannot be fired at",
+		},
+		{
+			"C fires first",
+			editMoves(singleText, func(moves []string) []string {
+				return moves[1:]
+			}),
+			"must open with P",
+		},
+		{
+			"P fires twice",
+			editMoves(singleText, func(moves []string) []string {
+				return append(moves[:1], moves[2:]...)
+			}),
+			"expected C to fire in turn 1",
+		},
+		{
+			"turn skipped",
+			editMoves(singleText, func(moves []string) []string {
+				moves[2] = "3" + strings.TrimPrefix(moves[2], "2")
+				return moves
+			}),
+			"expected P to fire in turn 2",
+		},
+		{
+			"move after the end",
+			editMoves(singleText, func(moves []string) []string {
+				return append(moves, "999. C: "+untouchedCell(t, single.PlayerBoard).String()+" miss")
+			}),
+			"already over",
+		},
+		{
+			"salvo in a single-shot game",
+			editMoves(singleText, func(moves []string) []string {
+				moves[0] += ", " + spare.String() + " miss"
+				return moves
+			}),
+			"single-shot game",
+		},
+		{
+			"salvo too large",
+			editMoves(fixedText, func(moves []string) []string {
+				moves[0] += ", " + fixedSpare.String() + " miss"
+				return moves
+			}),
+			"3 shots its salvo allows",
+		},
+		{
+			"chain goes on after a miss",
+			editMoves(chainText, func(moves []string) []string {
+				moves[chainRun] += ", " + chainSpare.String() + " miss"
+				return moves
+			}),
+			"ends the chain",
+		},
+		{
+			"chain stops after a hit",
+			editMoves(chainText, func(moves []string) []string {
+				moves[chainRun] = moves[chainRun][:strings.LastIndex(moves[chainRun], ", ")]
+				return moves
+			}),
+			"chain goes on",
+		},
+	}
+	for _, tt := range tests {
+		_, err := ParseNotation(strings.NewReader(tt.text))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ParseNotation() = %v, want an error about %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// ErrNoFleetLayout is returned by RandomFleet when a fleet cannot be laid out
+// on a board
+var ErrNoFleetLayout = errors.New("no fleet layout")
+
+// ErrLayoutSearchGaveUp is returned by RandomFleet when it stops looking for
+// a layout before finding one or ruling them all out, so the fleet may fit
+var ErrLayoutSearchGaveUp = errors.New("gave up looking for a fleet layout")
+
+// maxLayoutSteps bounds how many ships each of RandomFleet's searches tries to
+// place before it gives up, so a hopeless search cannot stall the game
+const maxLayoutSteps = 20000
+
+// placement is a position and orientation a ship could be placed at
+type placement struct {
+	pos         Position
+	orientation Orientation
+}
+
+// RandomFleet places every ship of a fleet on an empty board at random,
+// enforcing the placement rules. It backtracks when a ship has nowhere to go,
+// and returns an error wrapping ErrNoFleetLayout if there is no layout, or
+// ErrLayoutSearchGaveUp if none turns up within a bounded search. The board
+// is left empty on failure.
+func RandomFleet(board *Board, fleet Fleet, rules PlacementRules, rng *rand.Rand) error {
+	if len(board.Ships) > 0 {
+		return errors.New("board already has ships")
+	}
+
+	board.Rules = rules
+	random := &layoutSearch{board: board, rng: rng}
+	if random.place(fleet.Ships) {
+		return nil
+	}
+	board.clear()
+
+	// A tightly packed fleet may have so few layouts that a random search
+	// misses them, while one in reading order, from a random corner, finds
+	// them sooner
+	if random.steps >= maxLayoutSteps {
+		ordered := &layoutSearch{board: board, reverse: rng.Intn(2) == 0, last: map[int]int{}}
+		if ordered.place(fleet.Ships) {
+			return nil
+		}
+		board.clear()
+		if ordered.steps >= maxLayoutSteps {
+			return fmt.Errorf("%w: no room found for the %s fleet on a %dx%d board", ErrLayoutSearchGaveUp, fleet.Name, board.Rows, board.Cols)
+		}
+	}
+	return fmt.Errorf("%w: the %s fleet does not fit on a %dx%d board", ErrNoFleetLayout, fleet.Name, board.Rows, board.Cols)
+}
+
+// layoutSearch is a backtracking search for a fleet layout. Without an rng it
+// tries placements in order and only places a ship after the last ship of the
+// same length, so each layout is tried once and exhausting the search proves
+// there is none.
+type layoutSearch struct {
+	board   *Board
+	rng     *rand.Rand  // Shuffles each ship's placements, nil to try them in order
+	reverse bool        // In order, start from the bottom right corner
+	last    map[int]int // In order, the rank of the last ship placed of each length
+	steps   int         // Ships placed so far, up to maxLayoutSteps
+}
+
+// place places the given ships in order, undoing a ship if the ships after it
+// do not fit
+func (s *layoutSearch) place(ships []ShipSpec) bool {
+	if len(ships) == 0 {
+		return true
+	}
+	if s.board.freeCells() < totalLength(ships) {
+		return false
+	}
+
+	length := ships[0].Length
+	options := s.board.legalPlacements(length)
+	if s.rng != nil {
+		s.rng.Shuffle(len(options), func(i, j int) {
+			options[i], options[j] = options[j], options[i]
+		})
+	} else if s.reverse {
+		slices.Reverse(options)
+	}
+
+	for _, option := range options {
+		if s.steps >= maxLayoutSteps {
+			return false
+		}
+		rank := s.rank(option)
+		if last, ok := s.last[length]; ok && s.rng == nil && rank <= last {
+			continue
+		}
+		s.steps++
+
+		ship := NewShip(ships[0])
+		s.board.PlaceShip(ship, option.pos, option.orientation)
+		last, hadLast := s.last[length]
+		if s.rng == nil {
+			s.last[length] = rank
+		}
+		if s.place(ships[1:]) {
+			return true
+		}
+		if s.rng == nil {
+			if hadLast {
+				s.last[length] = last
+			} else {
+				delete(s.last, length)
+			}
+		}
+		s.board.removeShip(ship)
+	}
+	return false
+}
+
+// rank returns where a placement comes in the order the search tries them
+func (s *layoutSearch) rank(option placement) int {
+	rank := (option.pos.Row*s.board.Cols+option.pos.Col)*2 + int(option.orientation)
+	if s.reverse {
+		return -rank
+	}
+	return rank
+}
+
+// totalLength returns the number of cells the ships cover
+func totalLength(ships []ShipSpec) int {
+	total := 0
+	for _, spec := range ships {
+		total += spec.Length
+	}
+	return total
+}
+
+// freeCells returns the number of cells a ship could still be placed on
+func (b *Board) freeCells() int {
+	free := 0
+	for row := 0; row < b.Rows; row++ {
+		for col := 0; col < b.Cols; col++ {
+			pos := Position{Row: row, Col: col}
+			if b.Grid[row][col] == Empty && !b.IsBlocked(pos) {
+				free++
+			}
+		}
+	}
+	return free
+}
+
+// legalPlacements returns every position and orientation a ship of the given
+// length could be placed at
+func (b *Board) legalPlacements(length int) []placement {
+	var options []placement
+	for row := 0; row < b.Rows; row++ {
+		for col := 0; col < b.Cols; col++ {
+			for _, orientation := range []Orientation{Horizontal, Vertical} {
+				// A single cell lies the same way both ways
+				if length == 1 && orientation == Vertical {
+					continue
+				}
+				pos := Position{Row: row, Col: col}
+				if b.CanPlaceShip(pos, length, orientation) {
+					options = append(options, placement{pos: pos, orientation: orientation})
+				}
+			}
+		}
+	}
+	return options
+}
+
+// removeShip takes an unhit ship off the board
+func (b *Board) removeShip(ship *Ship) {
+	for i, placed := range b.Ships {
+		if placed == ship {
+			b.Ships = append(b.Ships[:i], b.Ships[i+1:]...)
+			break
+		}
+	}
+	b.liftShip(ship)
+}
+
+// clear removes every ship from the board
+func (b *Board) clear() {
+	for row := range b.Grid {
+		for col := range b.Grid[row] {
+			b.Grid[row][col] = Empty
+		}
+	}
+	b.Ships = b.Ships[:0]
+}
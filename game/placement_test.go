@@ -0,0 +1,157 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// singles returns a fleet of n ships of length 1
+func singles(n int) Fleet {
+	fleet := Fleet{Name: "Singles"}
+	for i := 1; i <= n; i++ {
+		fleet.Ships = append(fleet.Ships, ShipSpec{Name: fmt.Sprintf("Single %d", i), Length: 1})
+	}
+	return fleet
+}
+
+// checkLayout fails the test unless the board holds the whole fleet, in
+// order, obeying the placement rules
+func checkLayout(t *testing.T, b *Board, fleet Fleet, rules PlacementRules) {
+	t.Helper()
+	if len(b.Ships) != len(fleet.Ships) {
+		t.Fatalf("board has %d ships, want %d", len(b.Ships), len(fleet.Ships))
+	}
+	for i, ship := range b.Ships {
+		if ship.Name != fleet.Ships[i].Name || len(ship.Positions) != fleet.Ships[i].Length {
+			t.Errorf("ship %d is %s of length %d, want %+v", i, ship.Name, len(ship.Positions), fleet.Ships[i])
+		}
+		for _, pos := range ship.Positions {
+			if b.ShipAt(pos) != ship {
+				t.Errorf("%s is not on the board at %s", ship.Name, pos)
+			}
+			if !rules.NoTouch {
+				continue
+			}
+			for _, n := range neighbours(pos, b.Rows, b.Cols) {
+				if other := b.ShipAt(n); other != nil && other != ship {
+					t.Errorf("%s at %s touches %s at %s", ship.Name, pos, other.Name, n)
+				}
+			}
+		}
+	}
+}
+
+func TestRandomFleet(t *testing.T) {
+	tests := []struct {
+		rows, cols int
+		fleet      Fleet
+		rules      PlacementRules
+	}{
+		{10, 10, MiltonBradleyFleet, PlacementRules{}},
+		{10, 10, ClassicFleet, PlacementRules{NoTouch: true}},
+		{8, 12, SmallFleet, PlacementRules{NoTouch: true}},
+		{1, 5, Fleet{Name: "Carrier", Ships: []ShipSpec{{Name: "Carrier", Length: 5}}}, PlacementRules{}},
+		{5, 5, singles(9), PlacementRules{NoTouch: true}}, // Only fits as A1, C1, E1, A3, ...
+		{4, 4, singles(16), PlacementRules{}},
+	}
+	for _, tt := range tests {
+		for seed := int64(1); seed <= 5; seed++ {
+			b := NewBoard(tt.rows, tt.cols)
+			if err := RandomFleet(b, tt.fleet, tt.rules, rand.New(rand.NewSource(seed))); err != nil {
+				t.Errorf("RandomFleet(%dx%d, %s, %+v) = %v", tt.rows, tt.cols, tt.fleet.Name, tt.rules, err)
+				continue
+			}
+			checkLayout(t, b, tt.fleet, tt.rules)
+		}
+	}
+}
+
+func TestRandomFleetImpossible(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows, cols int
+		fleet      Fleet
+		rules      PlacementRules
+	}{
+		{"ship longer than the board", 4, 4, Fleet{Name: "Carrier", Ships: []ShipSpec{{Name: "Carrier", Length: 5}}}, PlacementRules{}},
+		{"more cells than the board", 3, 3, singles(10), PlacementRules{}},
+		{"too many singles apart", 5, 5, singles(10), PlacementRules{NoTouch: true}},
+		{"two destroyers apart on 2x2", 2, 2, Fleet{Name: "Pair", Ships: []ShipSpec{{Name: "A", Length: 2}, {Name: "B", Length: 2}}}, PlacementRules{NoTouch: true}},
+		{"large fleet apart on 6x6", 6, 6, MiltonBradleyFleet, PlacementRules{NoTouch: true}},
+	}
+	for _, tt := range tests {
+		b := NewBoard(tt.rows, tt.cols)
+		err := RandomFleet(b, tt.fleet, tt.rules, rand.New(rand.NewSource(1)))
+		if !errors.Is(err, ErrNoFleetLayout) {
+			t.Errorf("%s: RandomFleet() = %v, want ErrNoFleetLayout", tt.name, err)
+		}
+		if len(b.Ships) != 0 || b.freeCells() != tt.rows*tt.cols {
+			t.Errorf("%s: board was not left empty", tt.name)
+		}
+		if err := CheckBoardSize(tt.rows, tt.cols, tt.fleet, tt.rules); err == nil || strings.Contains(err.Error(), "no layout was found") {
+			t.Errorf("%s: CheckBoardSize() = %v, want the fleet not to fit", tt.name, err)
+		}
+	}
+}
+
+func TestRandomFleetGivesUp(t *testing.T) {
+	// 17 singles cannot be kept apart on 7x7, but proving it takes longer
+	// than the search is allowed
+	b := NewBoard(7, 7)
+	err := RandomFleet(b, singles(17), PlacementRules{NoTouch: true}, rand.New(rand.NewSource(1)))
+	if !errors.Is(err, ErrLayoutSearchGaveUp) || errors.Is(err, ErrNoFleetLayout) {
+		t.Errorf("RandomFleet() = %v, want ErrLayoutSearchGaveUp", err)
+	}
+	if len(b.Ships) != 0 {
+		t.Error("board was not left empty")
+	}
+
+	err = CheckBoardSize(7, 7, singles(17), PlacementRules{NoTouch: true})
+	if err == nil || strings.Contains(err.Error(), "does not fit") {
+		t.Errorf("CheckBoardSize() = %v, want an error that does not claim the fleet cannot fit", err)
+	}
+}
+
+func TestCheckBoardSizeTightFit(t *testing.T) {
+	if err := CheckBoardSize(5, 5, singles(9), PlacementRules{NoTouch: true}); err != nil {
+		t.Errorf("CheckBoardSize(5x5, 9 singles apart) = %v, want nil", err)
+	}
+}
+
+func TestRandomizePlacementKeepsComputerShots(t *testing.T) {
+	// One player shuffles their fleet twice, the other places the layout it
+	// ended up with by hand
+	shuffled, err := NewGameWithSeed(DefaultSettings(), 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := shuffled.RandomizePlacement(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	placed, err := NewGameWithSeed(DefaultSettings(), 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ship := range shuffled.PlayerBoard.Ships {
+		if !placed.PlacePlayerShip(ship.Positions[0], ship.Orientation()) {
+			t.Fatalf("cannot place %s", ship.Name)
+		}
+	}
+
+	for _, g := range []*Game{shuffled, placed} {
+		g.ConfirmFleet()
+		for i := 0; i < 20 && g.Phase == PlayerTurnPhase; i++ {
+			g.PlayerAttack(Position{Row: i / g.Cols, Col: i % g.Cols})
+			g.ComputerAttack()
+		}
+	}
+	if !reflect.DeepEqual(shuffled.Shots.Shots, placed.Shots.Shots) {
+		t.Error("randomizing the player's fleet changed the computer's shots")
+	}
+}
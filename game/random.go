@@ -0,0 +1,40 @@
+package game
+
+import "math/rand"
+
+// countingSource wraps a seeded rand.Source and counts how many values it has
+// produced, so a saved game can restore the generator to the same point
+type countingSource struct {
+	src   rand.Source64
+	draws uint64
+}
+
+// newCountingSource creates a counting source from a seed
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+// Int63 returns the next value from the underlying source
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+// Uint64 returns the next value from the underlying source
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+// Seed reseeds the underlying source and resets the draw count
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.draws = 0
+}
+
+// skip advances the source by n draws
+func (s *countingSource) skip(n uint64) {
+	for i := uint64(0); i < n; i++ {
+		s.Int63()
+	}
+}
@@ -0,0 +1,288 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReplayVersion is the version of the replay file format written by Replay.Save
+const ReplayVersion = 1
+
+// ErrCorruptReplay is returned when a replay file cannot be parsed or its
+// shots do not agree with its fleets
+var ErrCorruptReplay = errors.New("replay file is corrupted")
+
+// ShipPlacement is where a ship was placed at the start of a game
+type ShipPlacement struct {
+	Ship        string      `json:"ship"`
+	Position    Position    `json:"position"` // Bow of the ship, its top or leftmost cell
+	Orientation Orientation `json:"orientation"`
+}
+
+// Replay is a record of a finished game that can be stepped through turn by
+// turn: the settings, both fleets as they were placed and every shot fired
+type Replay struct {
+	Version       int             `json:"version"`
+	Settings      Settings        `json:"settings"`
+	Seed          int64           `json:"seed"`
+	Winner        string          `json:"winner"`
+	PlayerFleet   []ShipPlacement `json:"player_fleet"`
+	ComputerFleet []ShipPlacement `json:"computer_fleet"`
+	Shots         []ShotRecord    `json:"shots"`
+	volleys       [][]ShotRecord  // Shots grouped into turns
+}
+
+// NewReplay records a finished game. Both fleets must be known, so network
+// games cannot be replayed.
+func NewReplay(g *Game) (*Replay, error) {
+	if g.Phase != GameOverPhase {
+		return nil, errors.New("only a finished game can be replayed")
+	}
+	if g.Mode == Network {
+		return nil, errors.New("network games cannot be replayed")
+	}
+
+	r := &Replay{
+		Version:       ReplayVersion,
+		Settings:      g.Settings(),
+		Seed:          g.Seed,
+		Winner:        g.Winner,
+		PlayerFleet:   fleetPlacements(g.PlayerBoard),
+		ComputerFleet: fleetPlacements(g.ComputerBoard),
+		Shots:         g.Shots.Shots,
+	}
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// fleetPlacements returns where each ship on a board was placed
+func fleetPlacements(b *Board) []ShipPlacement {
+	placements := make([]ShipPlacement, len(b.Ships))
+	for i, ship := range b.Ships {
+		placements[i] = ShipPlacement{Ship: ship.Name, Position: ship.Positions[0], Orientation: ship.Orientation()}
+	}
+	return placements
+}
+
+// Save writes the replay to w
+func (r *Replay) Save(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// LoadReplay reads a replay written by Save and checks that every shot
+// it records could have been fired and had the recorded result
+func LoadReplay(rd io.Reader) (*Replay, error) {
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	var r Replay
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, ErrCorruptReplay
+	}
+	if r.Version != ReplayVersion {
+		return nil, fmt.Errorf("replay file version %d is not supported (expected %d)", r.Version, ReplayVersion)
+	}
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// check plays the whole game through, returning ErrCorruptReplay if the
+// fleets, shots or winner are not what the game could have produced
+func (r *Replay) check() error {
+	s := r.Settings
+	if s.Fleet.Validate() != nil || s.SalvoRule.Validate() != nil || CheckBoardSize(s.Rows, s.Cols, s.Fleet, s.Rules) != nil ||
+		s.Mode < VsComputer || s.Mode > HotSeat {
+		return ErrCorruptReplay
+	}
+
+	g, err := r.setup()
+	if err != nil {
+		return err
+	}
+
+	for _, shot := range r.Shots {
+		if shot.Shooter != PlayerSide && shot.Shooter != ComputerSide {
+			return ErrCorruptReplay
+		}
+		if err := g.replayShot(shot); err != nil {
+			return err
+		}
+	}
+
+	// The game ends when the last shot sinks the last ship of a fleet
+	if len(r.Shots) == 0 {
+		return ErrCorruptReplay
+	}
+	winner := r.Shots[len(r.Shots)-1].Shooter
+	if !g.Board(winner.Opponent()).AllShipsSunk() || r.Winner != g.SideName(winner) {
+		return ErrCorruptReplay
+	}
+	r.volleys = g.Shots.Volleys()
+	return nil
+}
+
+// setup creates the game at the start of the battle, with both fleets placed
+func (r *Replay) setup() (*Game, error) {
+	g := newGame(r.Settings, r.Seed)
+	if err := placeFleet(g.PlayerBoard, g.Fleet, r.PlayerFleet); err != nil {
+		return nil, err
+	}
+	if err := placeFleet(g.ComputerBoard, g.Fleet, r.ComputerFleet); err != nil {
+		return nil, err
+	}
+	g.CurrentShip = len(g.Fleet.Ships)
+	g.Phase = PlayerTurnPhase
+	g.Turn = 1
+	return g, nil
+}
+
+// placeFleet places a whole fleet on an empty board
+func placeFleet(b *Board, fleet Fleet, placements []ShipPlacement) error {
+	if len(placements) != len(fleet.Ships) {
+		return ErrCorruptReplay
+	}
+	for i, p := range placements {
+		if p.Ship != fleet.Ships[i].Name || !b.PlaceShip(NewShip(fleet.Ships[i]), p.Position, p.Orientation) {
+			return ErrCorruptReplay
+		}
+	}
+	return nil
+}
+
+// replayShot fires a recorded shot, checking it hits what the record says
+func (g *Game) replayShot(shot ShotRecord) error {
+	if g.checkShot(shot.Shooter, shot.Turn, shot.Target) != nil {
+		return ErrCorruptReplay
+	}
+
+	g.Turn = shot.Turn
+	hit, ship := g.Board(shot.Shooter.Opponent()).Attack(shot.Target)
+	if actual := g.recordShot(shot.Shooter, shot.Target, hit, ship); actual != shot {
+		return ErrCorruptReplay
+	}
+	return nil
+}
+
+// checkShot returns an error if a shot could not have been the next one
+// fired in the game. The player side fires first, the sides take turns and
+// a salvo holds no more shots than its rule allows.
+func (g *Game) checkShot(shooter Side, turn int, pos Position) error {
+	// A player may fire at any cell not yet attacked, even one the rules say
+	// cannot hold a ship
+	target := g.Board(shooter.Opponent())
+	if cell := target.GetCell(pos); !target.IsValidPosition(pos) || cell == Hit || cell == Miss {
+		return fmt.Errorf("%s cannot be fired at", pos)
+	}
+
+	shots := g.Shots.Shots
+	if len(shots) == 0 {
+		if shooter != PlayerSide || turn != 1 {
+			return errors.New("the game must open with P firing in turn 1")
+		}
+		return nil
+	}
+
+	// Another shot in the same turn is part of a salvo
+	last := shots[len(shots)-1]
+	chain := g.SalvoMode && g.SalvoRule.Kind == ChainShots
+	if shooter == last.Shooter && turn == last.Turn {
+		switch {
+		case !g.SalvoMode:
+			return fmt.Errorf("%s fired twice in turn %d of a single-shot game", notationSide(shooter), turn)
+		case chain:
+			if last.Result == ShotMiss {
+				return fmt.Errorf("%s missed, which ends the chain", notationSide(shooter))
+			}
+			if target.AllShipsSunk() {
+				return errors.New("the game is already over")
+			}
+		default:
+			fired := 0
+			for i := len(shots) - 1; i >= 0 && shots[i].Shooter == shooter && shots[i].Turn == turn; i-- {
+				fired++
+			}
+			if allowed := g.SalvoShots(shooter); fired >= allowed {
+				return fmt.Errorf("%s fired more than the %d shots its salvo allows", notationSide(shooter), allowed)
+			}
+		}
+		return nil
+	}
+
+	// A player's salvo is fired in full even once it has sunk the last ship,
+	// but nobody fires after that
+	if g.PlayerBoard.AllShipsSunk() || g.ComputerBoard.AllShipsSunk() {
+		return errors.New("the game is already over")
+	}
+	if chain && last.Result != ShotMiss {
+		return fmt.Errorf("%s hit, so the chain goes on", notationSide(last.Shooter))
+	}
+	next, nextTurn := ComputerSide, last.Turn
+	if last.Shooter == ComputerSide {
+		next, nextTurn = PlayerSide, last.Turn+1
+	}
+	if shooter != next || turn != nextTurn {
+		return fmt.Errorf("expected %s to fire in turn %d, not %s in turn %d", notationSide(next), nextTurn, notationSide(shooter), turn)
+	}
+	return nil
+}
+
+// Turns returns how many turns were taken, counting each side's shot or salvo
+// as a turn of its own
+func (r *Replay) Turns() int {
+	return len(r.volleys)
+}
+
+// Volley returns the shots fired in the given turn, counting from 1
+func (r *Replay) Volley(turn int) []ShotRecord {
+	return r.volleys[turn-1]
+}
+
+// GameAt rebuilds the game as it stood after the given number of turns, with
+// LastMessage describing the last of them. After the final turn the game is
+// over.
+func (r *Replay) GameAt(turns int) *Game {
+	turns = max(0, min(turns, len(r.volleys)))
+
+	// The replay was checked when it was loaded, so every shot is legal
+	g, _ := r.setup()
+	g.LastMessage = "The battle begins!"
+	for _, volley := range r.volleys[:turns] {
+		for _, shot := range volley {
+			g.replayShot(shot)
+		}
+		g.LastMessage = g.describeVolley(volley)
+		g.Phase = ComputerTurnPhase
+		if volley[0].Shooter == ComputerSide {
+			g.Phase = PlayerTurnPhase
+		}
+	}
+
+	if turns == len(r.volleys) {
+		g.Phase = GameOverPhase
+		g.Winner = r.Winner
+	}
+	return g
+}
+
+// describeVolley says what a side's shot or salvo hit
+func (g *Game) describeVolley(volley []ShotRecord) string {
+	shots := make([]string, len(volley))
+	for i, shot := range volley {
+		shots[i] = notationShot(shot)
+	}
+	return fmt.Sprintf("Turn %d: %s fired at %s", volley[0].Turn, g.SideName(volley[0].Shooter), strings.Join(shots, ", "))
+}
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// playAgainstComputer plays a game against the computer to the end, the
+// player firing at targets in order and then at every ship cell left
+func playAgainstComputer(t *testing.T, settings Settings, seed int64, targets []Position) *Game {
+	t.Helper()
+	g, err := NewGameWithSeed(settings, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RandomizePlacement(); err != nil {
+		t.Fatal(err)
+	}
+	g.ConfirmFleet()
+
+	for _, ship := range g.ComputerBoard.Ships {
+		targets = append(targets, ship.Positions...)
+	}
+	for g.Phase != GameOverPhase {
+		if g.Phase == ComputerTurnPhase {
+			g.ComputerAttack()
+			continue
+		}
+		for len(targets) > 0 && !g.IsValidSalvoTarget(targets[0]) {
+			targets = targets[1:]
+		}
+		if len(targets) == 0 {
+			t.Fatal("the player ran out of targets")
+		}
+		if !g.PlayerAttack(targets[0]) {
+			t.Fatalf("cannot fire at %s: %s", targets[0], g.LastMessage)
+		}
+		if g.SalvoMode && (g.GetSalvoShotsRemaining() == 0 || len(targets) == 1) {
+			g.ExecutePlayerSalvo()
+		}
+	}
+	return g
+}
+
+func TestReplayFiresAtRuledOutCells(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Rules.NoTouch = true
+
+	// Sink the computer's first ship, then fire next to it, where the rules
+	// say no ship can be
+	g, err := NewGameWithSeed(settings, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := g.ComputerBoard.Ships[0].Positions
+	ruledOut := neighbours(first[0], g.Rows, g.Cols)[0]
+	for _, pos := range first {
+		if ruledOut == pos {
+			t.Fatal("the neighbour is part of the ship")
+		}
+	}
+	g = playAgainstComputer(t, settings, 1, append(append([]Position{}, first...), ruledOut))
+	if g.ComputerBoard.GetCell(ruledOut) != Miss {
+		t.Fatalf("the player never fired at %s", ruledOut)
+	}
+
+	r, err := NewReplay(g)
+	if err != nil {
+		t.Fatalf("NewReplay() = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadReplay(&buf)
+	if err != nil {
+		t.Fatalf("LoadReplay() = %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Shots, g.Shots.Shots) {
+		t.Error("the loaded replay has different shots")
+	}
+}
+
+func TestLoadReplayRejectsRepeatedShot(t *testing.T) {
+	g := playAgainstComputer(t, DefaultSettings(), 1, nil)
+	r, err := NewReplay(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The computer fires its second shot at its first one again
+	r.Shots = append([]ShotRecord{}, r.Shots...)
+	r.Shots[3].Target = r.Shots[1].Target
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReplay(&buf); !errors.Is(err, ErrCorruptReplay) {
+		t.Errorf("LoadReplay() = %v, want ErrCorruptReplay", err)
+	}
+}
+
+func TestLoadReplayChecksWinner(t *testing.T) {
+	g := playAgainstComputer(t, DefaultSettings(), 1, nil)
+
+	tests := []struct {
+		name    string
+		corrupt func(r *Replay)
+	}{
+		{"other winner", func(r *Replay) {
+			r.Winner = g.SideName(PlayerSide)
+			if g.Winner == r.Winner {
+				r.Winner = g.SideName(ComputerSide)
+			}
+		}},
+		{"no winner", func(r *Replay) { r.Winner = "" }},
+		{"unfinished", func(r *Replay) { r.Shots = r.Shots[:len(r.Shots)-1] }},
+		{"no shots", func(r *Replay) { r.Shots = nil }},
+	}
+	for _, tt := range tests {
+		r, err := NewReplay(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tt.corrupt(r)
+		var buf bytes.Buffer
+		if err := r.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadReplay(&buf); !errors.Is(err, ErrCorruptReplay) {
+			t.Errorf("%s: LoadReplay() = %v, want ErrCorruptReplay", tt.name, err)
+		}
+	}
+}
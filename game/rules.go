@@ -0,0 +1,21 @@
+package game
+
+// PlacementRules are the house rules for where ships may be placed
+type PlacementRules struct {
+	NoTouch bool `json:"no_touch"` // Ships may not touch, not even diagonally
+}
+
+// neighbours returns the cells around pos, including diagonals, that are on
+// a board of the given size
+func neighbours(pos Position, rows, cols int) []Position {
+	cells := make([]Position, 0, 8)
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			p := Position{Row: pos.Row + dr, Col: pos.Col + dc}
+			if (dr != 0 || dc != 0) && p.Row >= 0 && p.Row < rows && p.Col >= 0 && p.Col < cols {
+				cells = append(cells, p)
+			}
+		}
+	}
+	return cells
+}
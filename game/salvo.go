@@ -0,0 +1,193 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SalvoRuleKind is how the number of shots in each salvo is decided
+type SalvoRuleKind int
+
+const (
+	ShotsPerShip SalvoRuleKind = iota // One shot for every ship still afloat
+	FixedShots                        // The same number of shots every turn
+	LargestShip                       // One shot for every unhit cell of the largest ship afloat
+	ChainShots                        // One shot at a time, firing again after every hit
+)
+
+// SalvoRule decides how many shots a side fires in each salvo
+type SalvoRule struct {
+	Kind  SalvoRuleKind `json:"kind"`
+	Shots int           `json:"shots,omitempty"` // Shots per turn with FixedShots
+}
+
+// SalvoRulePresets returns the salvo rules offered on the main menu
+func SalvoRulePresets() []SalvoRule {
+	return []SalvoRule{
+		{Kind: ShotsPerShip},
+		{Kind: FixedShots, Shots: 3},
+		{Kind: FixedShots, Shots: 5},
+		{Kind: LargestShip},
+		{Kind: ChainShots},
+	}
+}
+
+// String returns the display name of the rule, e.g. "Fixed 3"
+func (r SalvoRule) String() string {
+	switch r.Kind {
+	case ShotsPerShip:
+		return "One per ship"
+	case FixedShots:
+		return fmt.Sprintf("Fixed %d", r.Shots)
+	case LargestShip:
+		return "Largest ship"
+	case ChainShots:
+		return "Chain"
+	}
+	return "Unknown"
+}
+
+// ParseSalvoRule parses a rule written as its display name, ignoring case
+func ParseSalvoRule(s string) (SalvoRule, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	if n, ok := strings.CutPrefix(text, "fixed "); ok {
+		shots, err := strconv.Atoi(n)
+		if err != nil || shots < 1 {
+			return SalvoRule{}, fmt.Errorf("invalid number of shots %q", n)
+		}
+		return SalvoRule{Kind: FixedShots, Shots: shots}, nil
+	}
+	for _, rule := range SalvoRulePresets() {
+		if rule.Kind != FixedShots && strings.ToLower(rule.String()) == text {
+			return rule, nil
+		}
+	}
+	return SalvoRule{}, fmt.Errorf("unknown salvo rule %q, expected one per ship, fixed N, largest ship or chain", s)
+}
+
+// Validate returns an error if the rule cannot be played
+func (r SalvoRule) Validate() error {
+	if r.Kind < ShotsPerShip || r.Kind > ChainShots {
+		return fmt.Errorf("unknown salvo rule %d", r.Kind)
+	}
+	if r.Kind == FixedShots && r.Shots < 1 {
+		return fmt.Errorf("a fixed salvo needs at least one shot, not %d", r.Shots)
+	}
+	return nil
+}
+
+// SalvoShots returns how many shots a side fires in its next salvo
+func (g *Game) SalvoShots(shooter Side) int {
+	switch g.SalvoRule.Kind {
+	case FixedShots:
+		return g.SalvoRule.Shots
+	case LargestShip:
+		// Of the longest ships afloat, the one with the most cells left
+		var largest *Ship
+		for _, ship := range g.Board(shooter).Ships {
+			if ship.IsSunk() {
+				continue
+			}
+			if largest == nil || ship.Length > largest.Length ||
+				(ship.Length == largest.Length && unhitCells(ship) > unhitCells(largest)) {
+				largest = ship
+			}
+		}
+		if largest == nil {
+			return 0
+		}
+		return unhitCells(largest)
+	case ChainShots:
+		return 1
+	}
+	return g.GetRemainingShips(shooter == PlayerSide)
+}
+
+// unhitCells returns the number of a ship's cells that have not been hit
+func unhitCells(ship *Ship) int {
+	count := 0
+	for _, hit := range ship.Hits {
+		if !hit {
+			count++
+		}
+	}
+	return count
+}
+
+// SalvoResult is the outcome of a salvo, shot by shot in the order fired
+type SalvoResult struct {
+	Shooter Side
+	Shots   []ShotRecord
+}
+
+// Hits returns the number of shots in the salvo that hit a ship
+func (r SalvoResult) Hits() int {
+	count := 0
+	for _, shot := range r.Shots {
+		if shot.Result != ShotMiss {
+			count++
+		}
+	}
+	return count
+}
+
+// Misses returns the number of shots in the salvo that missed
+func (r SalvoResult) Misses() int {
+	return len(r.Shots) - r.Hits()
+}
+
+// Sunk returns the names of the ships the salvo sank
+func (r SalvoResult) Sunk() []string {
+	var names []string
+	for _, shot := range r.Shots {
+		if shot.Result == ShotSunk {
+			names = append(names, shot.Ship)
+		}
+	}
+	return names
+}
+
+// ShotAt returns the salvo's shot at pos, if it fired at pos
+func (r SalvoResult) ShotAt(pos Position) (ShotRecord, bool) {
+	for _, shot := range r.Shots {
+		if shot.Target == pos {
+			return shot, true
+		}
+	}
+	return ShotRecord{}, false
+}
+
+// Summary sums up the salvo, e.g. "Hits: 2 | Misses: 3 | Sunk: Destroyer"
+func (r SalvoResult) Summary() string {
+	var parts []string
+	if hits := r.Hits(); hits > 0 {
+		parts = append(parts, fmt.Sprintf("Hits: %d", hits))
+	}
+	if misses := r.Misses(); misses > 0 {
+		parts = append(parts, fmt.Sprintf("Misses: %d", misses))
+	}
+	if sunk := r.Sunk(); len(sunk) > 0 {
+		parts = append(parts, "Sunk: "+strings.Join(sunk, ", "))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// LastSalvo returns the most recent salvo a side fired, which is empty if it
+// has not fired one yet
+func (g *Game) LastSalvo(shooter Side) SalvoResult {
+	result := SalvoResult{Shooter: shooter}
+	shots := g.Shots.Shots
+
+	// The salvo is the side's last run of shots in a single turn
+	end := len(shots)
+	for end > 0 && shots[end-1].Shooter != shooter {
+		end--
+	}
+	start := end
+	for start > 0 && shots[start-1].Shooter == shooter && shots[start-1].Turn == shots[end-1].Turn {
+		start--
+	}
+	result.Shots = shots[start:end]
+	return result
+}
@@ -0,0 +1,344 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+)
+
+// SaveVersion is the version of the save file format written by Save
+const SaveVersion = 8
+
+// ErrCorruptSave is returned when a save file cannot be parsed or is inconsistent
+var ErrCorruptSave = errors.New("save file is corrupted")
+
+// savedShip is the serialized form of a Ship
+type savedShip struct {
+	Name      string     `json:"name"`
+	Positions []Position `json:"positions"`
+	Hits      []bool     `json:"hits"`
+}
+
+// savedBoard is the serialized form of a Board
+type savedBoard struct {
+	Rows  int           `json:"rows"`
+	Cols  int           `json:"cols"`
+	Grid  [][]CellState `json:"grid"`
+	Ships []savedShip   `json:"ships"`
+}
+
+// saveFile is the on-disk representation of an in-progress game
+type saveFile struct {
+	Version       int            `json:"version"`
+	Phase         GamePhase      `json:"phase"`
+	Rows          int            `json:"rows"`
+	Cols          int            `json:"cols"`
+	CurrentShip   int            `json:"current_ship"`
+	Fleet         Fleet          `json:"fleet"`
+	Rules         PlacementRules `json:"rules"`
+	Winner        string         `json:"winner"`
+	LastMessage   string         `json:"last_message"`
+	Thinking      string         `json:"thinking"`
+	Strategy      string         `json:"strategy"`
+	Mode          GameMode       `json:"mode"`
+	PlayerAI      string         `json:"player_strategy"`
+	SalvoMode     bool           `json:"salvo_mode"`
+	HitCountsOnly bool           `json:"hit_counts_only"`
+	SalvoRule     SalvoRule      `json:"salvo_rule"`
+	PlayerSalvo   []Position     `json:"player_salvo"`
+	Seed          int64          `json:"seed"`
+	Draws         uint64         `json:"draws"`
+	Turn          int            `json:"turn"`
+	Active        Side           `json:"active"`
+	Shots         []ShotRecord   `json:"shots"`
+	PlayerBoard   savedBoard     `json:"player_board"`
+	ComputerBoard savedBoard     `json:"computer_board"`
+}
+
+// Save writes the full game state to w
+func (g *Game) Save(w io.Writer) error {
+	var draws uint64
+	if g.rng != nil {
+		draws = g.rng.draws
+	}
+
+	save := saveFile{
+		Version:       SaveVersion,
+		Phase:         g.Phase,
+		Rows:          g.Rows,
+		Cols:          g.Cols,
+		CurrentShip:   g.CurrentShip,
+		Fleet:         g.Fleet,
+		Rules:         g.Rules,
+		Winner:        g.Winner,
+		LastMessage:   g.LastMessage,
+		Thinking:      g.ClaudeThinking,
+		Strategy:      g.ComputerStrategy,
+		Mode:          g.Mode,
+		PlayerAI:      g.PlayerStrategy,
+		SalvoMode:     g.SalvoMode,
+		HitCountsOnly: g.HitCountsOnly,
+		SalvoRule:     g.SalvoRule,
+		PlayerSalvo:   g.PlayerSalvo,
+		Seed:          g.Seed,
+		Draws:         draws,
+		Turn:          g.Turn,
+		Active:        g.Active,
+		Shots:         g.Shots.Shots,
+		PlayerBoard:   saveBoard(g.PlayerBoard),
+		ComputerBoard: saveBoard(g.ComputerBoard),
+	}
+
+	data, err := json.MarshalIndent(save, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// LoadGame reads a game previously written by Save
+func LoadGame(r io.Reader) (*Game, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var save saveFile
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, ErrCorruptSave
+	}
+
+	if save.Version != SaveVersion {
+		return nil, fmt.Errorf("save file version %d is not supported (expected %d)", save.Version, SaveVersion)
+	}
+
+	if save.Phase < PlacementPhase || (save.Phase > HandoffPhase && save.Phase != FleetReviewPhase) ||
+		save.Active < PlayerSide || save.Active > ComputerSide ||
+		CheckBoardSize(save.Rows, save.Cols, save.Fleet, save.Rules) != nil ||
+		save.Mode < VsComputer || save.Mode > HotSeat ||
+		save.CurrentShip < 0 || save.CurrentShip > len(save.Fleet.Ships) {
+		return nil, ErrCorruptSave
+	}
+
+	if save.Fleet.Validate() != nil || save.SalvoRule.Validate() != nil {
+		return nil, ErrCorruptSave
+	}
+
+	if _, ok := LookupStrategy(save.Strategy); !ok {
+		return nil, fmt.Errorf("save file uses unknown strategy %q", save.Strategy)
+	}
+	if save.Mode == ComputerVsComputer {
+		if _, ok := LookupStrategy(save.PlayerAI); !ok {
+			return nil, fmt.Errorf("save file uses unknown strategy %q", save.PlayerAI)
+		}
+	}
+
+	// The battle starts at turn 1, once every fleet is placed
+	setup := save.Phase == PlacementPhase || save.Phase == FleetReviewPhase
+	if save.Turn < 0 || (save.Turn == 0 && !setup && save.Phase != HandoffPhase) || (save.Turn > 0 && setup) {
+		return nil, ErrCorruptSave
+	}
+	if !slices.Contains(savedShipCounts(save, PlayerSide), len(save.PlayerBoard.Ships)) ||
+		!slices.Contains(savedShipCounts(save, ComputerSide), len(save.ComputerBoard.Ships)) {
+		return nil, ErrCorruptSave
+	}
+
+	playerBoard, err := loadBoard(save.PlayerBoard, save.Rows, save.Cols, save.Fleet, save.Rules)
+	if err != nil {
+		return nil, err
+	}
+	computerBoard, err := loadBoard(save.ComputerBoard, save.Rows, save.Cols, save.Fleet, save.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pos := range save.PlayerSalvo {
+		if !computerBoard.IsValidPosition(pos) {
+			return nil, ErrCorruptSave
+		}
+	}
+
+	if err := checkShots(save.Shots, playerBoard, computerBoard); err != nil {
+		return nil, err
+	}
+
+	rng := newCountingSource(save.Seed)
+	rng.skip(save.Draws)
+
+	g := &Game{
+		PlayerBoard:      playerBoard,
+		ComputerBoard:    computerBoard,
+		Phase:            save.Phase,
+		Rows:             save.Rows,
+		Cols:             save.Cols,
+		CurrentShip:      save.CurrentShip,
+		Fleet:            save.Fleet,
+		Rules:            save.Rules,
+		Winner:           save.Winner,
+		LastMessage:      save.LastMessage,
+		ClaudeThinking:   save.Thinking,
+		ComputerStrategy: save.Strategy,
+		Mode:             save.Mode,
+		PlayerStrategy:   save.PlayerAI,
+		Random:           rand.New(rng),
+		Seed:             save.Seed,
+		SalvoMode:        save.SalvoMode,
+		HitCountsOnly:    save.HitCountsOnly,
+		SalvoRule:        save.SalvoRule,
+		PlayerSalvo:      save.PlayerSalvo,
+		Shots:            ShotLedger{Shots: save.Shots},
+		Turn:             save.Turn,
+		Active:           save.Active,
+		rng:              rng,
+	}
+
+	return g, nil
+}
+
+// saveBoard converts a board to its serialized form
+func saveBoard(b *Board) savedBoard {
+	ships := make([]savedShip, len(b.Ships))
+	for i, ship := range b.Ships {
+		ships[i] = savedShip{
+			Name:      ship.Name,
+			Positions: ship.Positions,
+			Hits:      ship.Hits,
+		}
+	}
+
+	return savedBoard{
+		Rows:  b.Rows,
+		Cols:  b.Cols,
+		Grid:  b.Grid,
+		Ships: ships,
+	}
+}
+
+// checkShots returns ErrCorruptSave unless every shot in the ledger was fired
+// once, by one of the two sides, at a cell of the other side's board that
+// shows its result, and every cell fired at has a shot in the ledger
+func checkShots(shots []ShotRecord, playerBoard, computerBoard *Board) error {
+	fired := map[ShotRecord]bool{}
+	shotsAt := map[*Board]int{}
+	for _, shot := range shots {
+		target := computerBoard
+		switch shot.Shooter {
+		case PlayerSide:
+		case ComputerSide:
+			target = playerBoard
+		default:
+			return ErrCorruptSave
+		}
+		if !target.IsValidPosition(shot.Target) {
+			return ErrCorruptSave
+		}
+
+		cell := Hit
+		switch shot.Result {
+		case ShotMiss:
+			cell = Miss
+		case ShotHit, ShotSunk:
+		default:
+			return ErrCorruptSave
+		}
+		if target.GetCell(shot.Target) != cell {
+			return ErrCorruptSave
+		}
+
+		key := ShotRecord{Shooter: shot.Shooter, Target: shot.Target}
+		if fired[key] {
+			return ErrCorruptSave
+		}
+		fired[key] = true
+		shotsAt[target]++
+	}
+
+	// Each shot was at a different cell, so every cell fired at is in the
+	// ledger if the counts agree
+	if shotsAt[computerBoard] != attackedCells(computerBoard) || shotsAt[playerBoard] != attackedCells(playerBoard) {
+		return ErrCorruptSave
+	}
+	return nil
+}
+
+// attackedCells returns how many cells of a board have been fired at
+func attackedCells(b *Board) int {
+	count := 0
+	for _, row := range b.Grid {
+		for _, cell := range row {
+			if cell == Hit || cell == Miss {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// loadBoard rebuilds a board from its serialized form. Its ships are placed
+// again, in fleet order and by the rules, and with the saved hits and misses
+// must give exactly the saved grid.
+func loadBoard(saved savedBoard, rows, cols int, fleet Fleet, rules PlacementRules) (*Board, error) {
+	if saved.Rows != rows || saved.Cols != cols || len(saved.Grid) != rows || len(saved.Ships) > len(fleet.Ships) {
+		return nil, ErrCorruptSave
+	}
+	for row := range saved.Grid {
+		if len(saved.Grid[row]) != cols {
+			return nil, ErrCorruptSave
+		}
+	}
+
+	b := newRuledBoard(rows, cols, rules)
+	for i, s := range saved.Ships {
+		ship := NewShip(fleet.Ships[i])
+		if s.Name != ship.Name || len(s.Positions) != ship.Length || len(s.Hits) != ship.Length {
+			return nil, ErrCorruptSave
+		}
+
+		orientation := (&Ship{Positions: s.Positions}).Orientation()
+		if !b.PlaceShip(ship, s.Positions[0], orientation) || !slices.Equal(ship.Positions, s.Positions) {
+			return nil, ErrCorruptSave
+		}
+	}
+
+	for i, ship := range b.Ships {
+		ship.Hits = saved.Ships[i].Hits
+		for j, pos := range ship.Positions {
+			if ship.Hits[j] {
+				b.Grid[pos.Row][pos.Col] = Hit
+			}
+		}
+	}
+
+	for row := range saved.Grid {
+		for col, cell := range saved.Grid[row] {
+			if cell == Miss && b.Grid[row][col] == Empty {
+				b.Grid[row][col] = Miss
+			}
+			if b.Grid[row][col] != cell {
+				return nil, ErrCorruptSave
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// savedShipCounts returns how many ships a side's board may hold in a saved
+// game. In a hot-seat game the second player's board is empty until they
+// place their fleet, unless a rematch laid it out already.
+func savedShipCounts(save saveFile, side Side) []int {
+	all := len(save.Fleet.Ships)
+	switch {
+	case save.Turn > 0:
+		return []int{all}
+	case side == save.Active && (save.Phase == PlacementPhase || save.Phase == FleetReviewPhase):
+		return []int{save.CurrentShip}
+	case save.Mode == HotSeat && side == ComputerSide:
+		return []int{0, all}
+	}
+	return []int{all}
+}
@@ -0,0 +1,168 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// stackedGame starts a game against the computer with the player's fleet
+// stacked in the top left corner, one ship to a row, and places the first
+// ships of it
+func stackedGame(t *testing.T, settings Settings, ships int) *Game {
+	t.Helper()
+	g, err := NewGameWithSeed(settings, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < ships; i++ {
+		if !g.PlacePlayerShip(Position{Row: i, Col: 0}, Horizontal) {
+			t.Fatalf("cannot place ship %d", i)
+		}
+	}
+	return g
+}
+
+// battleGame returns a game a few turns into the battle, in which the
+// player has hit and missed
+func battleGame(t *testing.T) *Game {
+	t.Helper()
+	g := stackedGame(t, DefaultSettings(), len(DefaultFleet.Ships))
+	g.ConfirmFleet()
+	targets := []Position{g.ComputerBoard.Ships[0].Positions[0], untouchedCell(t, g.ComputerBoard), g.ComputerBoard.Ships[0].Positions[1]}
+	for _, pos := range targets {
+		if !g.PlayerAttack(pos) {
+			t.Fatalf("cannot fire at %s: %s", pos, g.LastMessage)
+		}
+		g.ComputerAttack()
+	}
+	return g
+}
+
+// saved returns the save file of a game
+func saved(t *testing.T, g *Game) saveFile {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := g.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var save saveFile
+	if err := json.Unmarshal(buf.Bytes(), &save); err != nil {
+		t.Fatal(err)
+	}
+	return save
+}
+
+// load loads a save file
+func load(t *testing.T, save saveFile) error {
+	t.Helper()
+	data, err := json.Marshal(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadGame(bytes.NewReader(data))
+	return err
+}
+
+func TestLoadGame(t *testing.T) {
+	hotSeat := DefaultSettings()
+	hotSeat.Mode = HotSeat
+
+	tests := []struct {
+		name string
+		game *Game
+	}{
+		{"placing", stackedGame(t, DefaultSettings(), 2)},
+		{"hot seat, first player placing", stackedGame(t, hotSeat, 3)},
+		{"battle", battleGame(t)},
+		{"finished", playAgainstComputer(t, DefaultSettings(), 1, nil)},
+	}
+	for _, tt := range tests {
+		if err := load(t, saved(t, tt.game)); err != nil {
+			t.Errorf("%s: LoadGame() = %v", tt.name, err)
+		}
+	}
+}
+
+func TestLoadGameRejectsCorruptBoards(t *testing.T) {
+	battle := saved(t, battleGame(t))
+	placing := saved(t, stackedGame(t, DefaultSettings(), 2))
+
+	tests := []struct {
+		name    string
+		save    saveFile
+		corrupt func(s *saveFile)
+	}{
+		{"computer ships missing, cells left", battle, func(s *saveFile) {
+			s.ComputerBoard.Ships = []savedShip{}
+		}},
+		{"player ships missing, cells left", battle, func(s *saveFile) {
+			s.PlayerBoard.Ships = []savedShip{}
+		}},
+		{"last ship missing", battle, func(s *saveFile) {
+			s.ComputerBoard.Ships = s.ComputerBoard.Ships[:len(s.ComputerBoard.Ships)-1]
+		}},
+		{"ship cell without a ship", battle, func(s *saveFile) {
+			s.PlayerBoard.Grid[9][9] = ShipCell
+		}},
+		{"duplicate ship", battle, func(s *saveFile) {
+			s.PlayerBoard.Ships[3] = s.PlayerBoard.Ships[2]
+		}},
+		{"overlapping ships", battle, func(s *saveFile) {
+			s.PlayerBoard.Ships[3].Positions = s.PlayerBoard.Ships[2].Positions
+			s.PlayerBoard.Grid[3][0], s.PlayerBoard.Grid[3][1], s.PlayerBoard.Grid[3][2] = Empty, Empty, Empty
+		}},
+		{"ships out of order", battle, func(s *saveFile) {
+			s.PlayerBoard.Ships[2], s.PlayerBoard.Ships[3] = s.PlayerBoard.Ships[3], s.PlayerBoard.Ships[2]
+		}},
+		{"ship not in a line", battle, func(s *saveFile) {
+			s.PlayerBoard.Ships[4].Positions[1] = Position{Row: 5, Col: 1}
+			s.PlayerBoard.Grid[4][1], s.PlayerBoard.Grid[5][1] = Empty, ShipCell
+		}},
+		{"ships touching under no-touch rules", battle, func(s *saveFile) {
+			s.Rules.NoTouch = true
+		}},
+		{"hit the grid does not show", battle, func(s *saveFile) {
+			s.ComputerBoard.Ships[1].Hits[0] = true
+		}},
+		{"miss without a shot", battle, func(s *saveFile) {
+			pos := untouchedCell(t, mustLoadBoard(t, s.ComputerBoard, s))
+			s.ComputerBoard.Grid[pos.Row][pos.Col] = Miss
+		}},
+		{"shot missing from the ledger", battle, func(s *saveFile) {
+			s.Shots = s.Shots[:len(s.Shots)-1]
+		}},
+		{"fewer ships than placed", placing, func(s *saveFile) {
+			s.CurrentShip = 3
+		}},
+		{"computer fleet missing during placement", placing, func(s *saveFile) {
+			s.ComputerBoard.Ships = []savedShip{}
+			s.ComputerBoard.Grid = NewBoard(s.Rows, s.Cols).Grid
+		}},
+		{"battle at turn 0", battle, func(s *saveFile) {
+			s.Turn = 0
+		}},
+	}
+	for _, tt := range tests {
+		// Corrupt a copy, leaving the fixture for the other cases
+		var save saveFile
+		data, _ := json.Marshal(tt.save)
+		json.Unmarshal(data, &save)
+		tt.corrupt(&save)
+
+		if err := load(t, save); !errors.Is(err, ErrCorruptSave) {
+			t.Errorf("%s: LoadGame() = %v, want ErrCorruptSave", tt.name, err)
+		}
+	}
+}
+
+// mustLoadBoard rebuilds a saved board of an uncorrupted save
+func mustLoadBoard(t *testing.T, b savedBoard, s *saveFile) *Board {
+	t.Helper()
+	board, err := loadBoard(b, s.Rows, s.Cols, s.Fleet, s.Rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return board
+}
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Settings are the choices a game is started with, everything needed to start
+// another game just like it
+type Settings struct {
+	Rows           int            `json:"rows"`
+	Cols           int            `json:"cols"`
+	Fleet          Fleet          `json:"fleet"`
+	Rules          PlacementRules `json:"rules"`
+	Mode           GameMode       `json:"mode"`
+	Strategy       string         `json:"strategy"`        // Strategy the computer side plays with
+	PlayerStrategy string         `json:"player_strategy"` // Strategy of the player side in a computer-vs-computer game
+	SalvoMode      bool           `json:"salvo_mode"`
+	HitCountsOnly  bool           `json:"hit_counts_only"` // Salvo results announce how many shots hit, not which
+	SalvoRule      SalvoRule      `json:"salvo_rule"`
+}
+
+// DefaultSettings returns the settings of a standard game against Captain Claude
+func DefaultSettings() Settings {
+	return Settings{
+		Rows:     10,
+		Cols:     10,
+		Fleet:    DefaultFleet,
+		Mode:     VsComputer,
+		Strategy: Easy.String(),
+	}
+}
+
+// Validate returns an error if a game cannot be started with the settings
+func (s Settings) Validate() error {
+	if err := s.Fleet.Validate(); err != nil {
+		return err
+	}
+	if err := CheckBoardSize(s.Rows, s.Cols, s.Fleet, s.Rules); err != nil {
+		return err
+	}
+	if s.HitCountsOnly && (!s.SalvoMode || s.Mode == Network) {
+		return errors.New("announcing only hit counts needs salvo mode, and is not available over the network")
+	}
+	if err := s.SalvoRule.Validate(); err != nil {
+		return err
+	}
+	if s.HitCountsOnly && s.SalvoRule.Kind == ChainShots {
+		return errors.New("a chain fires one shot at a time, so it cannot announce only hit counts")
+	}
+	if s.Mode == VsComputer || s.Mode == ComputerVsComputer {
+		if _, ok := LookupStrategy(s.Strategy); !ok {
+			return fmt.Errorf("unknown strategy %q", s.Strategy)
+		}
+	}
+	if s.Mode == ComputerVsComputer {
+		if _, ok := LookupStrategy(s.PlayerStrategy); !ok {
+			return fmt.Errorf("unknown strategy %q", s.PlayerStrategy)
+		}
+	}
+	return nil
+}
+
+// Settings returns the settings the game was started with
+func (g *Game) Settings() Settings {
+	return Settings{
+		Rows:           g.Rows,
+		Cols:           g.Cols,
+		Fleet:          g.Fleet,
+		Rules:          g.Rules,
+		Mode:           g.Mode,
+		Strategy:       g.ComputerStrategy,
+		PlayerStrategy: g.PlayerStrategy,
+		SalvoMode:      g.SalvoMode,
+		HitCountsOnly:  g.HitCountsOnly,
+		SalvoRule:      g.SalvoRule,
+	}
+}
+
+// NewGame creates a new game with a time-based random seed
+func NewGame(settings Settings) (*Game, error) {
+	return NewGameWithSeed(settings, time.Now().UnixNano())
+}
+
+// NewGameWithSeed creates a new game whose computer fleets and AI shots are
+// fully determined by the given seed. Computer-controlled fleets are placed
+// straight away, and a computer-vs-computer game is ready for the player side
+// to fire first. It returns an error if a fleet cannot be placed.
+//
+// In a network game the local player is always the player side, and the
+// computer board only tracks the results the remote opponent reports.
+func NewGameWithSeed(settings Settings, seed int64) (*Game, error) {
+	g := newGame(settings, seed)
+
+	switch settings.Mode {
+	case VsComputer:
+		if err := RandomFleet(g.ComputerBoard, g.Fleet, g.Rules, g.Random); err != nil {
+			return nil, err
+		}
+	case ComputerVsComputer:
+		if err := RandomFleet(g.ComputerBoard, g.Fleet, g.Rules, g.Random); err != nil {
+			return nil, err
+		}
+		if err := RandomFleet(g.PlayerBoard, g.Fleet, g.Rules, g.Random); err != nil {
+			return nil, err
+		}
+		g.CurrentShip = len(g.Fleet.Ships)
+		g.Phase = PlayerTurnPhase
+		g.Turn = 1
+	}
+
+	return g, nil
+}
+
+// Rematch starts a new game with the same settings. With sameLayout, each
+// human player's fleet is laid out as it was in this game, ready to review.
+func (g *Game) Rematch(seed int64, sameLayout bool) (*Game, error) {
+	next, err := NewGameWithSeed(g.Settings(), seed)
+	if err != nil || !sameLayout {
+		return next, err
+	}
+
+	switch next.Mode {
+	case VsComputer:
+		placeLayout(next.PlayerBoard, next.Fleet, layoutOf(g.PlayerBoard))
+	case HotSeat:
+		placeLayout(next.PlayerBoard, next.Fleet, layoutOf(g.PlayerBoard))
+		placeLayout(next.ComputerBoard, next.Fleet, layoutOf(g.ComputerBoard))
+	default:
+		return next, nil
+	}
+	next.CurrentShip = len(next.Fleet.Ships)
+	next.Phase = FleetReviewPhase
+	return next, nil
+}
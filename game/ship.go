@@ -1,19 +1,13 @@
 package game
 
-// ShipType represents different types of ships
-type ShipType int
-
-const (
-	Carrier ShipType = iota
-	Battleship
-	Cruiser
-	Submarine
-	Destroyer
+import (
+	"fmt"
+	"strconv"
+	"unicode"
 )
 
 // Ship represents a ship on the board
 type Ship struct {
-	Type      ShipType
 	Length    int
 	Positions []Position
 	Hits      []bool
@@ -22,8 +16,8 @@ type Ship struct {
 
 // Position represents a coordinate on the board
 type Position struct {
-	Row int
-	Col int
+	Row int `json:"row"`
+	Col int `json:"col"`
 }
 
 // Orientation represents ship placement direction
@@ -34,32 +28,13 @@ const (
 	Vertical
 )
 
-// NewShip creates a new ship of the given type
-func NewShip(shipType ShipType) *Ship {
-	ship := &Ship{
-		Type: shipType,
-	}
-
-	switch shipType {
-	case Carrier:
-		ship.Length = 5
-		ship.Name = "Carrier"
-	case Battleship:
-		ship.Length = 4
-		ship.Name = "Battleship"
-	case Cruiser:
-		ship.Length = 3
-		ship.Name = "Cruiser"
-	case Submarine:
-		ship.Length = 3
-		ship.Name = "Submarine"
-	case Destroyer:
-		ship.Length = 2
-		ship.Name = "Destroyer"
+// NewShip creates a new ship from its specification
+func NewShip(spec ShipSpec) *Ship {
+	return &Ship{
+		Length: spec.Length,
+		Hits:   make([]bool, spec.Length),
+		Name:   spec.Name,
 	}
-
-	ship.Hits = make([]bool, ship.Length)
-	return ship
 }
 
 // IsSunk returns true if all positions of the ship have been hit
@@ -82,3 +57,56 @@ func (s *Ship) Hit(pos Position) bool {
 	}
 	return false
 }
+
+// String returns the position in board notation, a column letter followed by
+// a 1-based row number, e.g. "B7"
+func (p Position) String() string {
+	return fmt.Sprintf("%c%d", 'A'+p.Col, p.Row+1)
+}
+
+// ParsePosition parses a position in board notation, e.g. "B7"
+func ParsePosition(s string) (Position, error) {
+	if len(s) < 2 {
+		return Position{}, fmt.Errorf("invalid position %q", s)
+	}
+
+	col := unicode.ToUpper(rune(s[0])) - 'A'
+	row, err := strconv.Atoi(s[1:])
+	if col < 0 || col >= 26 || err != nil || row < 1 {
+		return Position{}, fmt.Errorf("invalid position %q", s)
+	}
+	return Position{Row: row - 1, Col: int(col)}, nil
+}
+
+// NewSunkShip creates a fully hit ship, as revealed by an opponent who
+// reports that it has been sunk
+func NewSunkShip(name string, positions []Position) *Ship {
+	ship := &Ship{
+		Length:    len(positions),
+		Positions: positions,
+		Hits:      make([]bool, len(positions)),
+		Name:      name,
+	}
+	for i := range ship.Hits {
+		ship.Hits[i] = true
+	}
+	return ship
+}
+
+// Orientation returns the direction a placed ship lies in
+func (s *Ship) Orientation() Orientation {
+	if len(s.Positions) > 1 && s.Positions[1].Col == s.Positions[0].Col {
+		return Vertical
+	}
+	return Horizontal
+}
+
+// covers returns true if the ship occupies pos
+func (s *Ship) covers(pos Position) bool {
+	for _, p := range s.Positions {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Strategy chooses where a computer player fires. Strategies only ever see a
+// FogView of the opponent's board, never the board itself.
+type Strategy interface {
+	// NextTarget returns the next cell to fire at
+	NextTarget(view FogView, rng *rand.Rand) Position
+	// NextSalvo returns the cells to fire at in a salvo of the given size
+	NextSalvo(view FogView, shots int, rng *rand.Rand) []Position
+}
+
+// SequentialSalvo builds a salvo by asking a strategy for one target at a
+// time, without revealing the results of earlier shots in the salvo
+func SequentialSalvo(s Strategy, view FogView, shots int, rng *rand.Rand) []Position {
+	if available := view.targetCount(); shots > available {
+		shots = available
+	}
+
+	salvo := make([]Position, 0, shots)
+	for i := 0; i < shots; i++ {
+		pos := s.NextTarget(view, rng)
+		salvo = append(salvo, pos)
+		view = view.WithPending(pos)
+	}
+	return salvo
+}
+
+var (
+	strategies    = map[string]Strategy{}
+	strategyOrder []string
+)
+
+// RegisterStrategy makes a strategy available under a name. It panics if the
+// name is already registered.
+func RegisterStrategy(name string, s Strategy) {
+	if _, exists := strategies[name]; exists {
+		panic(fmt.Sprintf("game: strategy %q registered twice", name))
+	}
+	strategies[name] = s
+	strategyOrder = append(strategyOrder, name)
+}
+
+// LookupStrategy returns the strategy registered under a name
+func LookupStrategy(name string) (Strategy, bool) {
+	s, ok := strategies[name]
+	return s, ok
+}
+
+// StrategyNames returns the names of all registered strategies in the order
+// they were registered
+func StrategyNames() []string {
+	names := make([]string, len(strategyOrder))
+	copy(names, strategyOrder)
+	return names
+}
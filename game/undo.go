@@ -0,0 +1,93 @@
+package game
+
+// layout is the position and orientation of each placed ship, in fleet order
+type layout []placement
+
+// layoutOf returns the layout of the ships on a board
+func layoutOf(board *Board) layout {
+	l := make(layout, len(board.Ships))
+	for i, ship := range board.Ships {
+		l[i] = placement{pos: ship.Positions[0], orientation: ship.Orientation()}
+	}
+	return l
+}
+
+// placeLayout replaces the ships on a board with fresh ships of the fleet
+// laid out as given
+func placeLayout(board *Board, fleet Fleet, l layout) {
+	board.clear()
+	for i, p := range l {
+		board.PlaceShip(NewShip(fleet.Ships[i]), p.pos, p.orientation)
+	}
+}
+
+// layout returns the current layout of the active player's fleet
+func (g *Game) layout() layout {
+	return layoutOf(g.Board(g.Active))
+}
+
+// setLayout replaces the active player's fleet with the given layout, and
+// reviews it if it is complete
+func (g *Game) setLayout(l layout) {
+	placeLayout(g.Board(g.Active), g.Fleet, l)
+	g.CurrentShip = len(l)
+	g.Phase = PlacementPhase
+	if g.CurrentShip == len(g.Fleet.Ships) {
+		g.Phase = FleetReviewPhase
+	}
+}
+
+// recordPlacement remembers the layout before a placement change so it can be
+// undone. Any undone changes can no longer be redone.
+func (g *Game) recordPlacement(before layout) {
+	g.undo = append(g.undo, before)
+	g.redo = nil
+}
+
+// CanUndoPlacement returns true if there is a placement change to undo
+func (g *Game) CanUndoPlacement() bool {
+	return g.IsPlacing() && len(g.undo) > 0
+}
+
+// CanRedoPlacement returns true if there is an undone placement change to redo
+func (g *Game) CanRedoPlacement() bool {
+	return g.IsPlacing() && len(g.redo) > 0
+}
+
+// UndoPlacement rolls the active player's fleet back by one placement change
+func (g *Game) UndoPlacement() bool {
+	if !g.CanUndoPlacement() {
+		return false
+	}
+
+	g.redo = append(g.redo, g.layout())
+	g.setLayout(g.undo[len(g.undo)-1])
+	g.undo = g.undo[:len(g.undo)-1]
+	return true
+}
+
+// RedoPlacement reapplies the placement change most recently undone
+func (g *Game) RedoPlacement() bool {
+	if !g.CanRedoPlacement() {
+		return false
+	}
+
+	g.undo = append(g.undo, g.layout())
+	g.setLayout(g.redo[len(g.redo)-1])
+	g.redo = g.redo[:len(g.redo)-1]
+	return true
+}
+
+// MovePlayerShip moves or rotates one of the active player's placed ships
+func (g *Game) MovePlayerShip(ship *Ship, pos Position, orientation Orientation) bool {
+	if !g.IsPlacing() {
+		return false
+	}
+
+	before := g.layout()
+	if g.Board(g.Active).MoveShip(ship, pos, orientation) != nil {
+		return false
+	}
+	g.recordPlacement(before)
+	return true
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"battleship/game"
+	"flag"
 	"fmt"
 	"os"
 
@@ -8,7 +10,56 @@ import (
 )
 
 func main() {
-	p := tea.NewProgram(InitialModel(), tea.WithAltScreen())
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		if err := runSimulate(os.Args[2:], os.Stdout); err != nil {
+			fmt.Printf("Error running simulation: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		if err := runReplay(os.Args[2:], os.Stdout); err != nil {
+			fmt.Printf("Error running replay: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && (os.Args[1] == "host" || os.Args[1] == "join") {
+		run := runHost
+		if os.Args[1] == "join" {
+			run = runJoin
+		}
+		if err := run(os.Args[2:], os.Stdout); err != nil {
+			fmt.Printf("Error running network game: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	seed := flag.Int64("seed", 0, "seed for computer fleet placement and AI shots, to replay a game")
+	fleetName := flag.String("fleet", "", "built-in fleet name or path to a fleet JSON file")
+	flag.Parse()
+
+	var custom *game.Fleet
+	if *fleetName != "" {
+		fleet, err := resolveFleet(*fleetName)
+		if err != nil {
+			fmt.Printf("Error loading fleet: %v\n", err)
+			os.Exit(1)
+		}
+		custom = &fleet
+	}
+
+	useSeed := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			useSeed = true
+		}
+	})
+
+	p := tea.NewProgram(InitialModel(*seed, useSeed, custom), tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running program: %v\n", err)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"battleship/game"
+	"battleship/netplay"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,22 +14,64 @@ type Model struct {
 	cursorRow           int
 	cursorCol           int
 	shipOrientation     game.Orientation
+	moving              *game.Ship // Placed ship picked up to be moved, nil if none
 	showHelp            bool
 	computerThinking    bool
 	width               int
 	height              int
 	menuSelection       int
-	selectedDifficulty  game.Difficulty
-	selectedBoardSize   int
-	selectedSalvoMode   bool
+	settings            game.Settings // Settings chosen on the main menu
+	selectedBoardSize   int           // Side of a square board, or customBoardSize
+	customRows          int
+	customCols          int
+	editingSize         bool   // Typing a custom board size on the main menu
+	sizeInput           string // Custom board size typed so far
+	fleets              []game.Fleet // Fleets offered on the main menu
+	gameOverSelection   int          // Entry chosen on the game over screen
 	showAnimation       bool
 	animationType       string // "hit" or "miss"
 	lastAttackPos       game.Position
-	achievements        *Achievements
+	profile             *Profile // Statistics and achievements, saved between runs
 	newlyUnlocked       []Achievement
-	showAchievementsMenu bool
+	showAchievementsMenu bool // Achievements screen is open over the main menu
+	achievementScroll   int  // First achievement shown on the achievements screen
+	showStats           bool // Statistics screen is open over the main menu
+	menuMessage         string // Feedback shown on the main menu, e.g. a failed load
+	seed                int64  // Fixed seed from the command line
+	useSeed             bool   // Whether new games should use seed
+	net                 *netSession // Connection to a remote player, nil unless playing over the network
+	replay              *replaySession // Recorded game being viewed, nil unless replaying
+	replayPath          string         // Where the finished game was recorded, if it was
+	replayMessage       string         // Why the finished game could not be recorded, if it was not
+	exportMessage       string         // Outcome of exporting the finished game in game notation
 }
 
+// Main menu entries, in display order
+const (
+	menuMode = iota
+	menuBoardSize
+	menuFleet
+	menuRules
+	menuDifficulty
+	menuSalvo
+	menuSalvoRule
+	menuStart
+	menuContinue
+	menuAchievements
+	menuStats
+	menuQuit
+)
+
+// Game over screen entries, in display order
+const (
+	gameOverRematch = iota
+	gameOverSameLayout
+	gameOverMenu
+)
+
+// customBoardSize is the board size menu choice that uses customRows and customCols
+const customBoardSize = 0
+
 // computerTurnMsg is sent after a delay to simulate computer thinking
 type computerTurnMsg struct{}
 
@@ -45,11 +88,21 @@ func clearAnimation() tea.Msg {
 	return clearAnimationMsg{}
 }
 
-// InitialModel creates the initial model
-func InitialModel() Model {
-	g := game.NewGame(10)
+// InitialModel creates the initial model. If useSeed is set, every new game
+// is started from seed so it can be replayed exactly. A custom fleet, if
+// given, is selected and added to the fleets on the main menu.
+func InitialModel(seed int64, useSeed bool, custom *game.Fleet) Model {
+	settings := game.DefaultSettings()
+	if custom != nil {
+		settings.Fleet = *custom
+	}
+
+	// The default settings always make a valid game
+	g, _ := game.NewGame(game.DefaultSettings())
 	g.Phase = game.MainMenuPhase
 	return Model{
+		seed:              seed,
+		useSeed:           useSeed,
 		game:              g,
 		cursorRow:         0,
 		cursorCol:         0,
@@ -57,12 +110,19 @@ func InitialModel() Model {
 		showHelp:          false,
 		menuSelection:     0,
 		selectedBoardSize: 10,
-		achievements:      LoadAchievements(),
+		customRows:        10,
+		customCols:        15,
+		settings:          settings,
+		fleets:            menuFleets(custom),
+		profile:           LoadProfile(),
 	}
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
+	if m.net != nil {
+		return waitForPeer(m.net.peer)
+	}
 	return nil
 }
 
@@ -79,10 +139,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.game.ComputerAttack()
 			m.computerThinking = false
 
-			// Check for achievements if game ended
+			// Record the game if it just ended
 			if m.game.Phase == game.GameOverPhase {
-				m.newlyUnlocked = m.achievements.CheckAndUnlock(m.game)
+				m = m.endGame()
 			}
+			Autosave(m.game)
 		}
 		return m, nil
 
@@ -90,9 +151,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.showAnimation = false
 		return m, nil
 
+	case peerMsg:
+		return m.handlePeerMsg(msg)
+
 	case tea.KeyMsg:
+		if m.editingSize {
+			return m.handleSizeInput(msg)
+		}
+		if m.showStats {
+			return m.handleStatsKey(msg)
+		}
+		if m.showAchievementsMenu {
+			return m.handleAchievementsKey(msg)
+		}
+		if m.replay != nil {
+			return m.handleReplayKey(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
+			if m.net != nil && m.game.Phase != game.GameOverPhase {
+				m.sendPeer(netplay.Message{Kind: netplay.Resign})
+				m.game.Resign()
+				m.revealFleet()
+			}
+			Autosave(m.game)
 			return m, tea.Quit
 
 		case "h":
@@ -100,20 +183,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "r":
-			// Reset game
-			m.game = game.NewGame(10)
-			m.cursorRow = 0
-			m.cursorCol = 0
-			m.shipOrientation = game.Horizontal
-			m.showHelp = true
-			m.computerThinking = false
-			return m, nil
+			// A network game ends with the connection
+			if m.net != nil || m.game.Phase == game.MainMenuPhase {
+				return m, nil
+			}
+
+			// Restart with the settings of the current game
+			g, err := game.NewGameWithSeed(m.game.Settings(), m.gameSeed())
+			if err != nil {
+				m.game.LastMessage = err.Error()
+				return m, nil
+			}
+			return m.startGame(g), nil
 
 		case "up", "w":
 			if m.game.Phase == game.MainMenuPhase {
 				if m.menuSelection > 0 {
 					m.menuSelection--
 				}
+			} else if m.game.Phase == game.GameOverPhase {
+				if m.gameOverSelection > 0 {
+					m.gameOverSelection--
+				}
 			} else if m.cursorRow > 0 {
 				m.cursorRow--
 			}
@@ -121,18 +212,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "down", "s":
 			if m.game.Phase == game.MainMenuPhase {
-				if m.menuSelection < 4 { // 0 = Board Size, 1 = Difficulty, 2 = Salvo, 3 = Play, 4 = Quit
+				if m.menuSelection < menuQuit {
 					m.menuSelection++
 				}
-			} else if m.cursorRow < m.game.BoardSize-1 {
+			} else if m.game.Phase == game.GameOverPhase {
+				if m.gameOverSelection < gameOverMenu {
+					m.gameOverSelection++
+				}
+			} else if m.cursorRow < m.game.Rows-1 {
 				m.cursorRow++
 			}
 			return m, nil
 
 		case "left", "a":
-			if m.game.Phase == game.MainMenuPhase && m.menuSelection == 0 {
+			if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuMode {
+				m.settings.Mode = toggleMode(m.settings.Mode)
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuBoardSize {
 				// Cycle board size left
-				boardSizes := []int{8, 10, 12}
+				boardSizes := []int{8, 10, 12, customBoardSize}
 				for i, size := range boardSizes {
 					if size == m.selectedBoardSize {
 						if i == 0 {
@@ -143,25 +240,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						break
 					}
 				}
-			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == 1 {
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuFleet {
+				m.settings.Fleet = cycleFleet(m.fleets, m.settings.Fleet, -1)
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuRules {
+				// Toggle the no-touch rule
+				m.settings.Rules.NoTouch = !m.settings.Rules.NoTouch
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuDifficulty {
 				// Cycle difficulty left
-				if m.selectedDifficulty == game.Easy {
-					m.selectedDifficulty = game.Hard
-				} else {
-					m.selectedDifficulty--
-				}
-			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == 2 {
-				// Toggle salvo mode
-				m.selectedSalvoMode = !m.selectedSalvoMode
+				m.settings.Strategy = cycleStrategy(m.settings.Strategy, -1)
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuSalvo {
+				// Cycle salvo mode
+				m.settings = cycleSalvo(m.settings, -1)
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuSalvoRule {
+				m.settings.SalvoRule = cycleSalvoRule(m.settings.SalvoRule, -1)
 			} else if m.cursorCol > 0 {
 				m.cursorCol--
 			}
 			return m, nil
 
 		case "right", "d":
-			if m.game.Phase == game.MainMenuPhase && m.menuSelection == 0 {
+			if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuMode {
+				m.settings.Mode = toggleMode(m.settings.Mode)
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuBoardSize {
 				// Cycle board size right
-				boardSizes := []int{8, 10, 12}
+				boardSizes := []int{8, 10, 12, customBoardSize}
 				for i, size := range boardSizes {
 					if size == m.selectedBoardSize {
 						if i == len(boardSizes)-1 {
@@ -172,24 +274,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						break
 					}
 				}
-			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == 1 {
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuFleet {
+				m.settings.Fleet = cycleFleet(m.fleets, m.settings.Fleet, 1)
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuRules {
+				// Toggle the no-touch rule
+				m.settings.Rules.NoTouch = !m.settings.Rules.NoTouch
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuDifficulty {
 				// Cycle difficulty right
-				if m.selectedDifficulty == game.Hard {
-					m.selectedDifficulty = game.Easy
-				} else {
-					m.selectedDifficulty++
-				}
-			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == 2 {
-				// Toggle salvo mode
-				m.selectedSalvoMode = !m.selectedSalvoMode
-			} else if m.cursorCol < m.game.BoardSize-1 {
+				m.settings.Strategy = cycleStrategy(m.settings.Strategy, 1)
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuSalvo {
+				// Cycle salvo mode
+				m.settings = cycleSalvo(m.settings, 1)
+			} else if m.game.Phase == game.MainMenuPhase && m.menuSelection == menuSalvoRule {
+				m.settings.SalvoRule = cycleSalvoRule(m.settings.SalvoRule, 1)
+			} else if m.cursorCol < m.game.Cols-1 {
 				m.cursorCol++
 			}
 			return m, nil
 
 		case "o", "O":
 			// Toggle ship orientation during placement
-			if m.game.Phase == game.PlacementPhase {
+			if m.game.IsPlacing() {
 				if m.shipOrientation == game.Horizontal {
 					m.shipOrientation = game.Vertical
 				} else {
@@ -201,15 +306,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case " ", "enter":
 			return m.handleAction()
 
+		case "u", "U":
+			// Undo the last placement change
+			m.moving = nil
+			if m.game.UndoPlacement() {
+				Autosave(m.game)
+			}
+			return m, nil
+
+		case "y", "Y":
+			// Redo a placement change that was undone
+			m.moving = nil
+			if m.game.RedoPlacement() {
+				Autosave(m.game)
+			}
+			return m, nil
+
+		case "c", "C":
+			// Confirm the fleet once it has been reviewed
+			if m.moving == nil && m.game.ConfirmFleet() {
+				if m.net != nil && m.game.Phase == game.WaitingPhase {
+					m.commitFleet()
+				}
+				Autosave(m.game)
+			}
+			return m, nil
+
+		case "m", "M":
+			// Pick up the ship under the cursor, or put back the one being moved
+			if !m.game.IsPlacing() {
+				return m, nil
+			}
+			if m.moving != nil {
+				m.moving = nil
+				return m, nil
+			}
+			ship := m.game.Board(m.game.Active).ShipAt(game.Position{Row: m.cursorRow, Col: m.cursorCol})
+			if ship != nil {
+				m.moving = ship
+				m.shipOrientation = ship.Orientation()
+				m.cursorRow = ship.Positions[0].Row
+				m.cursorCol = ship.Positions[0].Col
+			}
+			return m, nil
+
+		case "x", "X":
+			// Place the whole fleet at random
+			if m.game.IsPlacing() {
+				m.moving = nil
+				if err := m.game.RandomizePlacement(); err != nil {
+					m.game.LastMessage = err.Error()
+					return m, nil
+				}
+				Autosave(m.game)
+			}
+			return m, nil
+
 		case "f", "F":
 			// Fire salvo
 			if m.game.Phase == game.PlayerTurnPhase && m.game.SalvoMode {
 				if len(m.game.PlayerSalvo) > 0 {
 					m.game.ExecutePlayerSalvo()
+					Autosave(m.game)
 
-					// Check for achievements if game ended (player won)
+					// Record the game if it just ended
 					if m.game.Phase == game.GameOverPhase {
-						m.newlyUnlocked = m.achievements.CheckAndUnlock(m.game)
+						m = m.endGame()
 					}
 
 					if m.game.Phase == game.ComputerTurnPhase {
@@ -219,9 +381,182 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return m, nil
+
+		case "e", "E":
+			// Export the finished game in game notation
+			if m.game.Phase == game.GameOverPhase && m.net == nil {
+				path, err := ExportNotation(m.game, m.replayPath)
+				if err != nil {
+					m.exportMessage = "Could not export the game: " + err.Error()
+				} else {
+					m.exportMessage = "Exported the game to " + path
+				}
+			}
+			return m, nil
+		}
+	}
+
+	return m, nil
+}
+
+// cycleStrategy returns the registered strategy step places away from current
+func cycleStrategy(current string, step int) string {
+	names := game.StrategyNames()
+	for i, name := range names {
+		if name == current {
+			return names[(i+step+len(names))%len(names)]
+		}
+	}
+	return names[0]
+}
+
+// cycleSalvo steps through the firing modes: single shots, salvos, and
+// salvos announcing only how many shots hit
+func cycleSalvo(settings game.Settings, step int) game.Settings {
+	current := 0
+	if settings.SalvoMode {
+		current = 1
+		if settings.HitCountsOnly {
+			current = 2
+		}
+	}
+	next := (current + step + 3) % 3
+	settings.SalvoMode = next > 0
+	settings.HitCountsOnly = next == 2
+	return settings
+}
+
+// cycleSalvoRule returns the salvo rule step places away from current
+func cycleSalvoRule(current game.SalvoRule, step int) game.SalvoRule {
+	rules := game.SalvoRulePresets()
+	for i, rule := range rules {
+		if rule == current {
+			return rules[(i+step+len(rules))%len(rules)]
+		}
+	}
+	return rules[0]
+}
+
+// toggleMode switches between playing Captain Claude and hot-seat play
+func toggleMode(mode game.GameMode) game.GameMode {
+	if mode == game.HotSeat {
+		return game.VsComputer
+	}
+	return game.HotSeat
+}
+
+// gameSeed returns the seed for a new game, the command-line seed if one was
+// given
+func (m Model) gameSeed() int64 {
+	if m.useSeed {
+		return m.seed
+	}
+	return time.Now().UnixNano()
+}
+
+// startGame switches to a newly created game
+func (m Model) startGame(g *game.Game) Model {
+	m.game = g
+	m.cursorRow = 0
+	m.cursorCol = 0
+	m.shipOrientation = game.Horizontal
+	m.moving = nil
+	m.showHelp = true
+	m.computerThinking = false
+	m.newlyUnlocked = nil
+	m.replayPath = ""
+	m.replayMessage = ""
+	m.exportMessage = ""
+	m.gameOverSelection = gameOverRematch
+	Autosave(m.game)
+	return m
+}
+
+// endGame records a game that has just ended, in the profile and as a replay
+func (m Model) endGame() Model {
+	m.newlyUnlocked = m.profile.RecordGame(m.game)
+	path, err := SaveReplay(m.game, time.Now())
+	if err != nil {
+		m.replayPath = ""
+		m.replayMessage = "Could not record a replay of the game: " + err.Error()
+		return m
+	}
+	m.replayPath = path
+	return m
+}
+
+// boardSize returns the rows and columns of the board chosen on the main menu
+func (m Model) boardSize() (rows, cols int) {
+	if m.selectedBoardSize == customBoardSize {
+		return m.customRows, m.customCols
+	}
+	return m.selectedBoardSize, m.selectedBoardSize
+}
+
+// handleSizeInput handles typing a custom board size on the main menu
+func (m Model) handleSizeInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.editingSize = false
+	case tea.KeyBackspace:
+		if len(m.sizeInput) > 0 {
+			m.sizeInput = m.sizeInput[:len(m.sizeInput)-1]
+		}
+	case tea.KeyEnter:
+		rows, cols, err := game.ParseBoardSize(m.sizeInput)
+		if err == nil {
+			err = game.CheckBoardSize(rows, cols, m.settings.Fleet, m.settings.Rules)
+		}
+		if err != nil {
+			m.menuMessage = err.Error()
+			return m, nil
 		}
+		m.customRows, m.customCols = rows, cols
+		m.editingSize = false
+		m.menuMessage = ""
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if (r >= '0' && r <= '9') || r == 'x' || r == 'X' {
+				m.sizeInput += string(r)
+			}
+		}
+	}
+	return m, nil
+}
+
+// handleStatsKey handles keys while the statistics screen is open
+func (m Model) handleStatsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "enter", " ", "backspace":
+		m.showStats = false
 	}
+	return m, nil
+}
 
+// handleAchievementsKey handles keys while the achievements screen is open
+func (m Model) handleAchievementsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	last := len(m.profile.Achievements.GetAll()) - achievementsPerPage(m.height)
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "enter", " ", "backspace":
+		m.showAchievementsMenu = false
+	case "up", "k":
+		m.achievementScroll--
+	case "down", "j":
+		m.achievementScroll++
+	case "pgup":
+		m.achievementScroll -= achievementsPerPage(m.height)
+	case "pgdown":
+		m.achievementScroll += achievementsPerPage(m.height)
+	case "home", "g":
+		m.achievementScroll = 0
+	case "end", "G":
+		m.achievementScroll = last
+	}
+	m.achievementScroll = max(0, min(m.achievementScroll, last))
 	return m, nil
 }
 
@@ -231,27 +566,69 @@ func (m Model) handleAction() (tea.Model, tea.Cmd) {
 
 	switch m.game.Phase {
 	case game.MainMenuPhase:
-		if m.menuSelection == 0 || m.menuSelection == 1 || m.menuSelection == 2 {
-			// Board Size, Difficulty, or Salvo Mode selection - do nothing, just cycle with arrow keys
+		m.menuMessage = ""
+		if m.menuSelection == menuBoardSize && m.selectedBoardSize == customBoardSize {
+			// Type in a custom size
+			m.editingSize = true
+			m.sizeInput = ""
 			return m, nil
-		} else if m.menuSelection == 3 {
-			// Start new game
-			m.game = game.NewGame(m.selectedBoardSize)
-			m.game.Difficulty = m.selectedDifficulty
-			m.game.SalvoMode = m.selectedSalvoMode
+		} else if m.menuSelection == menuMode || m.menuSelection == menuBoardSize || m.menuSelection == menuFleet || m.menuSelection == menuRules || m.menuSelection == menuDifficulty || m.menuSelection == menuSalvo || m.menuSelection == menuSalvoRule {
+			// Mode, Board Size, Fleet, Ships Touching, Difficulty, Salvo Mode or Salvo Shots selection - do nothing, just cycle with arrow keys
+			return m, nil
+		} else if m.menuSelection == menuContinue {
+			// Restore the last autosaved game
+			g, err := LoadAutosave()
+			if err != nil {
+				m.menuMessage = err.Error()
+				return m, nil
+			}
+			m.game = g
 			m.cursorRow = 0
 			m.cursorCol = 0
 			m.shipOrientation = game.Horizontal
+			m.moving = nil
 			m.showHelp = true
 			m.computerThinking = false
+			if m.game.Phase == game.ComputerTurnPhase {
+				m.computerThinking = true
+				return m, computerTurn
+			}
+		} else if m.menuSelection == menuStart {
+			// Start new game
+			m.settings.Rows, m.settings.Cols = m.boardSize()
+			if err := m.settings.Validate(); err != nil {
+				m.menuMessage = err.Error()
+				return m, nil
+			}
+			g, err := game.NewGameWithSeed(m.settings, m.gameSeed())
+			if err != nil {
+				m.menuMessage = err.Error()
+				return m, nil
+			}
+			return m.startGame(g), nil
+		} else if m.menuSelection == menuAchievements {
+			m.showAchievementsMenu = true
+			m.achievementScroll = 0
+		} else if m.menuSelection == menuStats {
+			m.showStats = true
 		} else {
 			// Quit
 			return m, tea.Quit
 		}
 		return m, nil
 
-	case game.PlacementPhase:
-		m.game.PlacePlayerShip(pos, m.shipOrientation)
+	case game.PlacementPhase, game.FleetReviewPhase:
+		if m.moving != nil {
+			// Put the picked up ship down at the cursor
+			if m.game.MovePlayerShip(m.moving, pos, m.shipOrientation) {
+				m.moving = nil
+				Autosave(m.game)
+			}
+			return m, nil
+		}
+		if m.game.PlacePlayerShip(pos, m.shipOrientation) {
+			Autosave(m.game)
+		}
 		return m, nil
 
 	case game.PlayerTurnPhase:
@@ -259,9 +636,17 @@ func (m Model) handleAction() (tea.Model, tea.Cmd) {
 		m.lastAttackPos = pos
 
 		// Check current cell state to determine if it will be hit or miss
-		cell := m.game.ComputerBoard.GetCell(pos)
+		cell := m.game.Board(m.game.Active.Opponent()).GetCell(pos)
 
 		if m.game.PlayerAttack(pos) {
+			// The result arrives from the remote player
+			if m.net != nil {
+				m.fireAtPeer(pos)
+				return m, nil
+			}
+
+			Autosave(m.game)
+
 			// Trigger animation for non-salvo mode
 			if !m.game.SalvoMode {
 				m.showAnimation = true
@@ -272,9 +657,9 @@ func (m Model) handleAction() (tea.Model, tea.Cmd) {
 				}
 			}
 
-			// Check for achievements if game ended (player won)
+			// Record the game if it just ended
 			if m.game.Phase == game.GameOverPhase {
-				m.newlyUnlocked = m.achievements.CheckAndUnlock(m.game)
+				m = m.endGame()
 			}
 
 			if m.game.Phase == game.ComputerTurnPhase {
@@ -292,9 +677,32 @@ func (m Model) handleAction() (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case game.GameOverPhase:
-		// Could restart on enter
+	case game.HandoffPhase:
+		// The next player has the keyboard, lift the curtain
+		m.game.EndHandoff()
+		m.cursorRow = 0
+		m.cursorCol = 0
+		m.shipOrientation = game.Horizontal
+		Autosave(m.game)
 		return m, nil
+
+	case game.GameOverPhase:
+		// A network game ends with the connection
+		if m.net != nil {
+			return m, nil
+		}
+
+		if m.gameOverSelection == gameOverMenu {
+			m.game.Phase = game.MainMenuPhase
+			m.newlyUnlocked = nil
+			return m, nil
+		}
+		g, err := m.game.Rematch(m.gameSeed(), m.gameOverSelection == gameOverSameLayout)
+		if err != nil {
+			m.game.LastMessage = err.Error()
+			return m, nil
+		}
+		return m.startGame(g), nil
 	}
 
 	return m, nil
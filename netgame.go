@@ -0,0 +1,277 @@
+package main
+
+import (
+	"battleship/game"
+	"battleship/netplay"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// netSession holds the state of a game against a remote player
+type netSession struct {
+	peer         *netplay.Peer
+	localReady   bool                 // Our fleet is placed and COMMIT was sent
+	remoteReady  bool                 // The opponent's COMMIT has arrived
+	commitment   game.FleetCommitment // Our commitment, revealed once the game is over
+	opponentHash string               // The opponent's commitment
+	revealed     bool                 // Our REVEAL has been sent
+	verdict      string               // Outcome of checking the opponent's REVEAL
+}
+
+// peerMsg carries a message received from the remote player
+type peerMsg struct {
+	msg netplay.Message
+	err error
+}
+
+// waitForPeer waits for the next message from the remote player
+func waitForPeer(p *netplay.Peer) tea.Cmd {
+	return func() tea.Msg {
+		msg, err := p.Receive()
+		return peerMsg{msg: msg, err: err}
+	}
+}
+
+// runHost runs the host subcommand, waiting for a player to join on an address
+func runHost(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("host", flag.ContinueOnError)
+	boardSize := fs.String("size", "10", "board size, e.g. 10 or 8x12")
+	fleetName := fs.String("fleet", game.DefaultFleet.Name, "built-in fleet name or path to a fleet JSON file")
+	noTouch := fs.Bool("notouch", false, "ships may not touch, not even diagonally")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: battleship host [-size rows x cols] [-fleet name] [-notouch] :port")
+	}
+	fleet, err := resolveFleet(*fleetName)
+	if err != nil {
+		return err
+	}
+	rows, cols, err := game.ParseBoardSize(*boardSize)
+	if err != nil {
+		return err
+	}
+	rules := game.PlacementRules{NoTouch: *noTouch}
+	if err := game.CheckBoardSize(rows, cols, fleet, rules); err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	fmt.Fprintf(out, "Waiting for an opponent to join on %s...\n", ln.Addr())
+	peer, err := netplay.Host(ln, rows, cols, rules, fleet)
+	if err != nil {
+		return err
+	}
+	return playNetworkGame(peer)
+}
+
+// runJoin runs the join subcommand, connecting to a hosted game
+func runJoin(args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New("usage: battleship join host:port")
+	}
+
+	fmt.Fprintf(out, "Joining %s...\n", args[0])
+	peer, err := netplay.Join(args[0])
+	if err != nil {
+		return err
+	}
+	return playNetworkGame(peer)
+}
+
+// playNetworkGame runs the terminal UI for a connected game
+func playNetworkGame(peer *netplay.Peer) error {
+	defer peer.Close()
+
+	p := tea.NewProgram(NetworkModel(peer), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// NetworkModel creates a model for a game against a connected remote player,
+// starting with fleet placement
+func NetworkModel(peer *netplay.Peer) Model {
+	m := InitialModel(0, false, nil)
+	m.settings.Rows, m.settings.Cols = peer.BoardSize()
+	m.settings.Fleet = peer.Fleet()
+	m.settings.Rules = peer.Rules()
+	m.settings.Mode = game.Network
+
+	// A network game places no computer fleet, so it cannot fail to start
+	m.game, _ = game.NewGameWithSeed(m.settings, time.Now().UnixNano())
+	m.showHelp = true
+	m.net = &netSession{peer: peer}
+	return m
+}
+
+// sendPeer sends a message to the remote player, abandoning the game if the
+// connection has gone
+func (m Model) sendPeer(msg netplay.Message) {
+	if err := m.net.peer.Send(msg); err != nil {
+		m.game.Abandon("Connection lost: " + err.Error())
+	}
+}
+
+// commitFleet tells the remote player our fleet is placed, and starts the
+// battle if theirs already is
+func (m Model) commitFleet() {
+	commitment, err := game.CommitFleet(m.game.PlayerBoard)
+	if err != nil {
+		m.game.Abandon("Could not commit to your fleet: " + err.Error())
+		return
+	}
+
+	m.net.commitment = commitment
+	m.net.localReady = true
+	m.sendPeer(netplay.CommitMessage(commitment.Hash))
+	if m.net.remoteReady {
+		m.game.StartNetworkBattle(m.net.peer.IsHost())
+	}
+}
+
+// fireAtPeer sends our shot to the remote player
+func (m Model) fireAtPeer(pos game.Position) {
+	m.sendPeer(netplay.ShotMessage(pos))
+}
+
+// revealFleet reveals our fleet layout once the game is over, so the remote
+// player can check it against our commitment
+func (m Model) revealFleet() {
+	if !m.net.localReady || m.net.revealed {
+		return
+	}
+
+	msg, err := netplay.RevealMessage(m.net.commitment.Reveal)
+	if err != nil {
+		return
+	}
+	m.net.revealed = true
+	m.net.peer.Send(msg)
+}
+
+// verifyOpponent checks the remote player's revealed fleet against their
+// commitment and every result they reported
+func (m Model) verifyOpponent(msg netplay.Message) {
+	reveal, err := netplay.ParseReveal(msg)
+	if err == nil && !m.net.remoteReady {
+		err = errors.New("they never committed to a fleet")
+	}
+
+	var discrepancies []game.Discrepancy
+	if err == nil {
+		discrepancies, err = m.game.VerifyOpponentFleet(m.net.opponentHash, reveal)
+	}
+
+	switch {
+	case err != nil:
+		m.net.verdict = "✘ Your opponent's fleet could not be verified: " + err.Error()
+	case len(discrepancies) > 0:
+		m.net.verdict = fmt.Sprintf("✘ Your opponent misreported %d shot(s), first on %s", len(discrepancies), discrepancies[0])
+	default:
+		m.net.verdict = "✔ Your opponent's fleet checks out: every result was reported honestly"
+	}
+}
+
+// protocolError hangs up on a remote player that broke the protocol
+func (m Model) protocolError(err error) (tea.Model, tea.Cmd) {
+	m.net.peer.Send(netplay.ErrorMessage(err.Error()))
+	m.net.peer.Close()
+	m.game.Abandon("Game abandoned: " + err.Error())
+	return m, nil
+}
+
+// handlePeerMsg applies a message from the remote player
+func (m Model) handlePeerMsg(msg peerMsg) (tea.Model, tea.Cmd) {
+	if m.game.Phase == game.GameOverPhase {
+		// The only message expected after the game is the opponent's REVEAL
+		if msg.err == nil && msg.msg.Kind == netplay.Reveal {
+			m.verifyOpponent(msg.msg)
+			return m, nil
+		}
+		if msg.err == nil {
+			return m, waitForPeer(m.net.peer)
+		}
+		if m.net.verdict == "" && m.net.remoteReady {
+			m.net.verdict = "✘ Your opponent left without revealing their fleet"
+		}
+		return m, nil
+	}
+	if msg.err != nil {
+		m.game.Abandon("Connection lost: " + msg.err.Error())
+		return m, nil
+	}
+
+	switch msg.msg.Kind {
+	case netplay.Commit:
+		// A second commitment would let the opponent move their ships mid-game
+		if m.net.remoteReady {
+			return m.protocolError(errors.New("opponent committed to a fleet twice"))
+		}
+		hash, err := netplay.ParseCommit(msg.msg)
+		if err != nil {
+			return m.protocolError(err)
+		}
+		m.net.remoteReady = true
+		m.net.opponentHash = hash
+		if m.net.localReady {
+			m.game.StartNetworkBattle(m.net.peer.IsHost())
+		}
+
+	case netplay.Shot:
+		pos, err := netplay.ParseShot(msg.msg)
+		if err != nil {
+			return m.protocolError(err)
+		}
+		result, ship, err := m.game.ReceiveShot(pos)
+		if err != nil {
+			return m.protocolError(err)
+		}
+		m.sendPeer(netplay.ResultMessage(pos, result, ship))
+		if m.game.Phase == game.GameOverPhase {
+			m.revealFleet()
+		}
+
+	case netplay.Result:
+		pos, result, ship, err := netplay.ParseResult(msg.msg)
+		if err != nil {
+			return m.protocolError(err)
+		}
+		if err := m.game.ApplyShotResult(pos, result, ship); err != nil {
+			return m.protocolError(err)
+		}
+		m.lastAttackPos = pos
+		m.showAnimation = true
+		m.animationType = "miss"
+		if result != game.ShotMiss {
+			m.animationType = "hit"
+		}
+		if m.game.Phase == game.GameOverPhase {
+			m.revealFleet()
+		}
+		return m, tea.Batch(clearAnimation, waitForPeer(m.net.peer))
+
+	case netplay.Resign:
+		m.game.OpponentResigned()
+		m.revealFleet()
+
+	default:
+		return m.protocolError(fmt.Errorf("unexpected message %q", msg.msg))
+	}
+
+	return m, waitForPeer(m.net.peer)
+}
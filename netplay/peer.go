@@ -0,0 +1,169 @@
+package netplay
+
+import (
+	"battleship/game"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+)
+
+// Peer is one end of a connection between two players
+type Peer struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	host   bool
+	setup  Setup
+}
+
+// NewPeer wraps an established connection. The host fires first.
+func NewPeer(conn net.Conn, host bool) *Peer {
+	return &Peer{conn: conn, reader: bufio.NewReader(conn), host: host}
+}
+
+// Host waits for a player to join on the listener and agrees the board
+// dimensions, placement rules and fleet with them
+func Host(ln net.Listener, rows, cols int, rules game.PlacementRules, fleet game.Fleet) (*Peer, error) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewPeer(conn, true)
+	if err := p.HostHandshake(rows, cols, rules, fleet); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// Join connects to a hosted game and learns its board dimensions, placement
+// rules and fleet
+func Join(addr string) (*Peer, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewPeer(conn, false)
+	if err := p.JoinHandshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// HostHandshake sends the host's HELLO and checks the joiner's reply
+func (p *Peer) HostHandshake(rows, cols int, rules game.PlacementRules, fleet game.Fleet) error {
+	hello, err := HelloMessage(rows, cols, rules, fleet)
+	if err != nil {
+		return err
+	}
+	if err := p.Send(hello); err != nil {
+		return err
+	}
+
+	m, err := p.Receive()
+	if err != nil {
+		return err
+	}
+	joined, err := ParseHello(m)
+	if err != nil {
+		p.Send(ErrorMessage(err.Error()))
+		return err
+	}
+	want := Setup{Version: ProtocolVersion, Rows: rows, Cols: cols, Rules: rules, Fleet: fleet}
+	if !reflect.DeepEqual(joined, want) {
+		err := fmt.Errorf("opponent speaks protocol %d on a %dx%d board, expected protocol %d on %dx%d with the %s fleet",
+			joined.Version, joined.Rows, joined.Cols, ProtocolVersion, rows, cols, fleet.Name)
+		p.Send(ErrorMessage(err.Error()))
+		return err
+	}
+
+	p.setup = want
+	return nil
+}
+
+// JoinHandshake reads the host's HELLO and echoes it back if the protocol
+// versions match
+func (p *Peer) JoinHandshake() error {
+	m, err := p.Receive()
+	if err != nil {
+		return err
+	}
+	setup, err := ParseHello(m)
+	if err != nil {
+		p.Send(ErrorMessage(err.Error()))
+		return err
+	}
+	if setup.Version != ProtocolVersion {
+		err := fmt.Errorf("host speaks protocol %d, expected %d", setup.Version, ProtocolVersion)
+		p.Send(ErrorMessage(err.Error()))
+		return err
+	}
+
+	hello, err := HelloMessage(setup.Rows, setup.Cols, setup.Rules, setup.Fleet)
+	if err != nil {
+		return err
+	}
+	if err := p.Send(hello); err != nil {
+		return err
+	}
+	p.setup = setup
+	return nil
+}
+
+// IsHost returns true if this end hosts the game and fires first
+func (p *Peer) IsHost() bool {
+	return p.host
+}
+
+// BoardSize returns the number of rows and columns agreed in the handshake
+func (p *Peer) BoardSize() (rows, cols int) {
+	return p.setup.Rows, p.setup.Cols
+}
+
+// Rules returns the placement rules agreed in the handshake
+func (p *Peer) Rules() game.PlacementRules {
+	return p.setup.Rules
+}
+
+// Fleet returns the fleet agreed in the handshake
+func (p *Peer) Fleet() game.Fleet {
+	return p.setup.Fleet
+}
+
+// Send writes a message to the other player
+func (p *Peer) Send(m Message) error {
+	_, err := io.WriteString(p.conn, m.String()+"\n")
+	return err
+}
+
+// Receive blocks until the next message from the other player arrives. An
+// ERROR message from the other player is returned as an error.
+func (p *Peer) Receive() (Message, error) {
+	line, err := p.reader.ReadString('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Message{}, errors.New("opponent disconnected")
+		}
+		return Message{}, err
+	}
+
+	m, err := ParseMessage(line)
+	if err != nil {
+		return Message{}, err
+	}
+	if m.Kind == Error {
+		return Message{}, errors.New("opponent reported an error: " + strings.Join(m.Args, " "))
+	}
+	return m, nil
+}
+
+// Close hangs up the connection
+func (p *Peer) Close() error {
+	return p.conn.Close()
+}
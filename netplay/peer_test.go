@@ -0,0 +1,340 @@
+package netplay
+
+import (
+	"battleship/game"
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// listen opens a listener on a free loopback port
+func listen(t *testing.T) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	return ln
+}
+
+// connect hosts a game on a loopback port and joins it
+func connect(t *testing.T, rows, cols int, rules game.PlacementRules, fleet game.Fleet) (host, joiner *Peer) {
+	t.Helper()
+	ln := listen(t)
+
+	type hosted struct {
+		peer *Peer
+		err  error
+	}
+	done := make(chan hosted)
+	go func() {
+		p, err := Host(ln, rows, cols, rules, fleet)
+		done <- hosted{p, err}
+	}()
+
+	joiner, err := Join(ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := <-done
+	if h.err != nil {
+		t.Fatal(h.err)
+	}
+	t.Cleanup(func() {
+		h.peer.Close()
+		joiner.Close()
+	})
+	return h.peer, joiner
+}
+
+// hostWithReply hosts a game and answers its HELLO with reply from a joiner
+// speaking the raw protocol, returning the host's error and what the joiner
+// received next
+func hostWithReply(t *testing.T, reply Message) (error, error) {
+	t.Helper()
+	ln := listen(t)
+
+	done := make(chan error)
+	go func() {
+		p, err := Host(ln, 10, 10, game.PlacementRules{}, game.DefaultFleet)
+		if p != nil {
+			p.Close()
+		}
+		done <- err
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	joiner := NewPeer(conn, false)
+	defer joiner.Close()
+	if _, err := joiner.Receive(); err != nil {
+		t.Fatal(err)
+	}
+	if err := joiner.Send(reply); err != nil {
+		t.Fatal(err)
+	}
+	_, received := joiner.Receive()
+	return <-done, received
+}
+
+func TestHandshake(t *testing.T) {
+	rules := game.PlacementRules{NoTouch: true}
+	host, joiner := connect(t, 8, 12, rules, game.SmallFleet)
+
+	if !host.IsHost() || joiner.IsHost() {
+		t.Errorf("IsHost() = %v for the host and %v for the joiner", host.IsHost(), joiner.IsHost())
+	}
+	for _, p := range []*Peer{host, joiner} {
+		if rows, cols := p.BoardSize(); rows != 8 || cols != 12 {
+			t.Errorf("BoardSize() = %dx%d, want 8x12", rows, cols)
+		}
+		if p.Rules() != rules {
+			t.Errorf("Rules() = %+v, want %+v", p.Rules(), rules)
+		}
+		if !reflect.DeepEqual(p.Fleet(), game.SmallFleet) {
+			t.Errorf("Fleet() = %+v, want %+v", p.Fleet(), game.SmallFleet)
+		}
+	}
+}
+
+func TestHandshakeKeepsWhitespaceInNames(t *testing.T) {
+	fleet := game.Fleet{Name: "Grand  Armada\tNo.\u00a01", Ships: []game.ShipSpec{
+		{Name: "Sea\u00a0Wolf", Length: 3},
+		{Name: "Night Heron", Length: 2},
+	}}
+	host, joiner := connect(t, 8, 8, game.PlacementRules{}, fleet)
+	for _, p := range []*Peer{host, joiner} {
+		if !reflect.DeepEqual(p.Fleet(), fleet) {
+			t.Errorf("Fleet() = %+v, want %+v", p.Fleet(), fleet)
+		}
+	}
+
+	reveal := game.FleetReveal{Ships: []game.FleetShip{{Name: "Sea\u00a0Wolf"}}, Nonce: "n"}
+	m, err := RevealMessage(reveal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseMessage(m.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ParseReveal(parsed); err != nil || !reflect.DeepEqual(got, reveal) {
+		t.Errorf("ParseReveal() = %+v, %v, want %+v", got, err, reveal)
+	}
+}
+
+func TestHandshakeVersionMismatch(t *testing.T) {
+	hello, err := HelloMessage(10, 10, game.PlacementRules{}, game.DefaultFleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hello.Args[0] = strconv.Itoa(ProtocolVersion + 1)
+
+	hostErr, joinerErr := hostWithReply(t, hello)
+	if hostErr == nil || !strings.Contains(hostErr.Error(), "protocol") {
+		t.Errorf("Host() error = %v, want a protocol mismatch", hostErr)
+	}
+	if joinerErr == nil || !strings.Contains(joinerErr.Error(), "opponent reported an error") {
+		t.Errorf("joiner received %v, want the host's ERROR", joinerErr)
+	}
+}
+
+func TestHandshakeFleetMismatch(t *testing.T) {
+	hello, err := HelloMessage(10, 10, game.PlacementRules{}, game.SmallFleet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hostErr, joinerErr := hostWithReply(t, hello)
+	if hostErr == nil || !strings.Contains(hostErr.Error(), game.DefaultFleet.Name) {
+		t.Errorf("Host() error = %v, want a fleet mismatch", hostErr)
+	}
+	if joinerErr == nil {
+		t.Error("joiner was not sent an ERROR")
+	}
+}
+
+func TestJoinRejectsOtherVersion(t *testing.T) {
+	ln := listen(t)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		hello, err := HelloMessage(10, 10, game.PlacementRules{}, game.DefaultFleet)
+		if err != nil {
+			return
+		}
+		hello.Args[0] = strconv.Itoa(ProtocolVersion + 1)
+		host := NewPeer(conn, true)
+		host.Send(hello)
+		host.Receive()
+	}()
+
+	_, err := Join(ln.Addr().String())
+	if err == nil || !strings.Contains(err.Error(), "host speaks protocol") {
+		t.Errorf("Join() error = %v, want a protocol mismatch", err)
+	}
+}
+
+// networkGame starts a network game with its fleet placed and committed
+func networkGame(t *testing.T, p *Peer, seed int64) (*game.Game, game.FleetCommitment) {
+	t.Helper()
+	rows, cols := p.BoardSize()
+	g, err := game.NewGameWithSeed(game.Settings{Rows: rows, Cols: cols, Fleet: p.Fleet(), Rules: p.Rules(), Mode: game.Network}, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RandomizePlacement(); err != nil {
+		t.Fatal(err)
+	}
+	g.ConfirmFleet()
+
+	commitment, err := game.CommitFleet(g.PlayerBoard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g, commitment
+}
+
+// receive reads the next message, which must be of the given kind
+func receive(t *testing.T, p *Peer, kind string) Message {
+	t.Helper()
+	m, err := p.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Kind != kind {
+		t.Fatalf("received %q, want %s", m, kind)
+	}
+	return m
+}
+
+// exchangeShot fires a shot from one game at the other over the connection
+// and applies the reported result
+func exchangeShot(t *testing.T, shooter *game.Game, from *Peer, target *game.Game, to *Peer, pos game.Position) game.ShotResult {
+	t.Helper()
+	if !shooter.PlayerAttack(pos) {
+		t.Fatalf("cannot fire at %s: %s", pos, shooter.LastMessage)
+	}
+	from.Send(ShotMessage(pos))
+
+	received, err := ParseShot(receive(t, to, Shot))
+	if err != nil || received != pos {
+		t.Fatalf("ParseShot() = %s, %v, want %s", received, err, pos)
+	}
+	result, ship, err := target.ReceiveShot(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to.Send(ResultMessage(received, result, ship))
+
+	reportedPos, reported, sunk, err := ParseResult(receive(t, from, Result))
+	if err != nil || reportedPos != pos || reported != result {
+		t.Fatalf("ParseResult() = %s %v, %v, want %s %v", reportedPos, reported, err, pos, result)
+	}
+	if result == game.ShotSunk && (sunk == nil || !reflect.DeepEqual(sunk.Positions, ship.Positions) || sunk.Name != ship.Name) {
+		t.Fatalf("ParseResult() revealed %+v, want %+v", sunk, ship)
+	}
+	if err := shooter.ApplyShotResult(reportedPos, reported, sunk); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// emptyCell returns a cell of a board that holds no ship and was never fired at
+func emptyCell(t *testing.T, b *game.Board) game.Position {
+	t.Helper()
+	for row := 0; row < b.Rows; row++ {
+		for col := 0; col < b.Cols; col++ {
+			pos := game.Position{Row: row, Col: col}
+			if b.GetCell(pos) == game.Empty {
+				return pos
+			}
+		}
+	}
+	t.Fatal("no empty cell left")
+	return game.Position{}
+}
+
+func TestBattleOverLoopback(t *testing.T) {
+	host, joiner := connect(t, 10, 10, game.PlacementRules{}, game.DefaultFleet)
+	hostGame, hostCommitment := networkGame(t, host, 1)
+	joinerGame, joinerCommitment := networkGame(t, joiner, 2)
+
+	// A result before the battle has started answers no shot
+	if err := hostGame.ApplyShotResult(game.Position{}, game.ShotMiss, nil); !errors.Is(err, game.ErrNotYourTurn) {
+		t.Errorf("ApplyShotResult() before the battle = %v, want ErrNotYourTurn", err)
+	}
+
+	host.Send(CommitMessage(hostCommitment.Hash))
+	joiner.Send(CommitMessage(joinerCommitment.Hash))
+	joinerHash, err := ParseCommit(receive(t, host, Commit))
+	if err != nil || joinerHash != joinerCommitment.Hash {
+		t.Fatalf("ParseCommit() = %q, %v", joinerHash, err)
+	}
+	if _, err := ParseCommit(receive(t, joiner, Commit)); err != nil {
+		t.Fatal(err)
+	}
+	hostGame.StartNetworkBattle(true)
+	joinerGame.StartNetworkBattle(false)
+
+	// The host sinks the joiner's last ship while the joiner only misses
+	target := joinerGame.PlayerBoard.Ships[len(joinerGame.PlayerBoard.Ships)-1]
+	var results []game.ShotResult
+	for _, pos := range target.Positions {
+		results = append(results, exchangeShot(t, hostGame, host, joinerGame, joiner, pos))
+		exchangeShot(t, joinerGame, joiner, hostGame, host, emptyCell(t, hostGame.PlayerBoard))
+	}
+	for i, result := range results {
+		want := game.ShotHit
+		if i == len(results)-1 {
+			want = game.ShotSunk
+		}
+		if result != want {
+			t.Errorf("shot %d = %v, want %v", i+1, result, want)
+		}
+	}
+	if len(hostGame.ComputerBoard.Ships) != 1 || hostGame.ComputerBoard.Ships[0].Name != target.Name {
+		t.Errorf("host's tracking board has %v, want the sunk %s", hostGame.ComputerBoard.Ships, target.Name)
+	}
+
+	// With no shot in flight, an unsolicited result is refused
+	if err := hostGame.ApplyShotResult(emptyCell(t, hostGame.ComputerBoard), game.ShotMiss, nil); !errors.Is(err, game.ErrNotYourTurn) {
+		t.Errorf("unsolicited ApplyShotResult() = %v, want ErrNotYourTurn", err)
+	}
+
+	// Starting the battle again, as a repeated COMMIT would, changes nothing
+	turn := hostGame.Turn
+	hostGame.PlayerAttack(emptyCell(t, hostGame.ComputerBoard))
+	hostGame.StartNetworkBattle(true)
+	if hostGame.Turn != turn || hostGame.Phase != game.WaitingPhase {
+		t.Errorf("StartNetworkBattle() with a shot in flight moved to turn %d, phase %v", hostGame.Turn, hostGame.Phase)
+	}
+
+	// The joiner resigns and reveals its fleet, which checks out
+	joiner.Send(Message{Kind: Resign})
+	receive(t, host, Resign)
+	hostGame.OpponentResigned()
+
+	reveal, err := RevealMessage(joinerCommitment.Reveal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	joiner.Send(reveal)
+	revealed, err := ParseReveal(receive(t, host, Reveal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	discrepancies, err := hostGame.VerifyOpponentFleet(joinerHash, revealed)
+	if err != nil || len(discrepancies) > 0 {
+		t.Errorf("VerifyOpponentFleet() = %v, %v, want an honest fleet", discrepancies, err)
+	}
+}
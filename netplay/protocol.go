@@ -0,0 +1,255 @@
+// Package netplay implements the line protocol spoken between two battleship
+// instances playing each other over a network.
+//
+// Every message is a single line of space-separated fields, starting with the
+// message kind:
+//
+//	HELLO <version> <rows>x<cols> <rules> <fleet>  handshake, sent by the host then echoed by the joiner
+//	COMMIT <hash>                                  the sender's fleet is placed, with a commitment to its layout
+//	SHOT <cell>                                    fire at a cell, e.g. SHOT B7
+//	RESULT <cell> MISS|HIT                         the result of the last shot
+//	RESULT <cell> SUNK <cells> <name>              the last shot sank a ship, e.g. RESULT B7 SUNK B6,B7 Destroyer
+//	RESIGN                                         the sender gives up
+//	REVEAL <data>                                  after the game, the sender's layout and nonce, to check the commitment
+//	ERROR <reason>                                 the sender hit a protocol error and is hanging up
+//
+// The rules in HELLO are TOUCH or NOTOUCH, saying whether ships may touch, and
+// the fleet is the JSON form of a game.Fleet. The host chooses both. JSON is
+// sent base64-encoded, so that spaces inside names survive being split into
+// fields.
+// Each side keeps its own fleet private and only reports the results of the
+// shots fired at it. The host fires first. The commitment sent with COMMIT is
+// a hash of the fleet layout and a secret nonce (see game.CommitFleet), so
+// once the layout is revealed at the end of the game the other side can check
+// that it never moved and that every reported result was true.
+package netplay
+
+import (
+	"battleship/game"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProtocolVersion is the version of the protocol spoken by this build
+const ProtocolVersion = 6
+
+// Message kinds
+const (
+	Hello  = "HELLO"
+	Commit = "COMMIT"
+	Shot   = "SHOT"
+	Result = "RESULT"
+	Resign = "RESIGN"
+	Reveal = "REVEAL"
+	Error  = "ERROR"
+)
+
+// ErrMalformed is returned when a message cannot be parsed
+var ErrMalformed = errors.New("malformed message")
+
+// Message is a single protocol message
+type Message struct {
+	Kind string
+	Args []string
+}
+
+// String formats the message as it is sent on the wire, without the newline
+func (m Message) String() string {
+	return strings.Join(append([]string{m.Kind}, m.Args...), " ")
+}
+
+// ParseMessage parses a line received from the wire
+func ParseMessage(line string) (Message, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return Message{}, ErrMalformed
+	}
+	return Message{Kind: strings.ToUpper(fields[0]), Args: fields[1:]}, nil
+}
+
+// Setup is the game announced in a HELLO message
+type Setup struct {
+	Version int
+	Rows    int
+	Cols    int
+	Rules   game.PlacementRules
+	Fleet   game.Fleet
+}
+
+// HelloMessage announces the protocol version, board dimensions, placement
+// rules and fleet
+func HelloMessage(rows, cols int, rules game.PlacementRules, fleet game.Fleet) (Message, error) {
+	data, err := json.Marshal(fleet)
+	if err != nil {
+		return Message{}, err
+	}
+	touch := "TOUCH"
+	if rules.NoTouch {
+		touch = "NOTOUCH"
+	}
+	args := []string{strconv.Itoa(ProtocolVersion), fmt.Sprintf("%dx%d", rows, cols), touch, base64.StdEncoding.EncodeToString(data)}
+	return Message{Kind: Hello, Args: args}, nil
+}
+
+// ParseHello returns the game setup announced by a HELLO message
+func ParseHello(m Message) (Setup, error) {
+	var h Setup
+	if m.Kind != Hello || len(m.Args) < 4 {
+		return h, fmt.Errorf("%w: expected HELLO, got %q", ErrMalformed, m)
+	}
+	version, err := strconv.Atoi(m.Args[0])
+	if err != nil {
+		return h, fmt.Errorf("%w: bad version %q", ErrMalformed, m.Args[0])
+	}
+	h.Version = version
+	if version != ProtocolVersion {
+		// The rest of the message may be laid out differently
+		return h, nil
+	}
+
+	switch strings.ToUpper(m.Args[2]) {
+	case "TOUCH":
+	case "NOTOUCH":
+		h.Rules.NoTouch = true
+	default:
+		return h, fmt.Errorf("%w: bad rules %q", ErrMalformed, m.Args[2])
+	}
+	if len(m.Args) != 4 {
+		return h, fmt.Errorf("%w: expected HELLO, got %q", ErrMalformed, m)
+	}
+	if err := unmarshalArg(m.Args[3], &h.Fleet); err != nil {
+		return h, fmt.Errorf("%w: bad fleet: %v", ErrMalformed, err)
+	}
+	if err := h.Fleet.Validate(); err != nil {
+		return h, fmt.Errorf("%w: bad fleet: %v", ErrMalformed, err)
+	}
+	h.Rows, h.Cols, err = game.ParseBoardSize(m.Args[1])
+	if err == nil {
+		err = game.CheckBoardSize(h.Rows, h.Cols, h.Fleet, h.Rules)
+	}
+	if err != nil {
+		return h, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return h, nil
+}
+
+// CommitMessage announces that the sender's fleet has been placed, along with
+// the hash committing to its layout
+func CommitMessage(hash string) Message {
+	return Message{Kind: Commit, Args: []string{hash}}
+}
+
+// ParseCommit returns the commitment hash of a COMMIT message
+func ParseCommit(m Message) (string, error) {
+	if m.Kind != Commit || len(m.Args) != 1 {
+		return "", fmt.Errorf("%w: expected COMMIT, got %q", ErrMalformed, m)
+	}
+	return m.Args[0], nil
+}
+
+// ShotMessage fires at a cell
+func ShotMessage(pos game.Position) Message {
+	return Message{Kind: Shot, Args: []string{pos.String()}}
+}
+
+// ParseShot returns the cell fired at by a SHOT message
+func ParseShot(m Message) (game.Position, error) {
+	if m.Kind != Shot || len(m.Args) != 1 {
+		return game.Position{}, fmt.Errorf("%w: expected SHOT, got %q", ErrMalformed, m)
+	}
+	pos, err := game.ParsePosition(m.Args[0])
+	if err != nil {
+		return game.Position{}, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return pos, nil
+}
+
+// ResultMessage reports the result of a shot. ship is the sunk ship and is
+// only used when the result is game.ShotSunk.
+func ResultMessage(pos game.Position, result game.ShotResult, ship *game.Ship) Message {
+	switch result {
+	case game.ShotSunk:
+		cells := make([]string, len(ship.Positions))
+		for i, p := range ship.Positions {
+			cells[i] = p.String()
+		}
+		return Message{Kind: Result, Args: []string{pos.String(), "SUNK", strings.Join(cells, ","), ship.Name}}
+	case game.ShotHit:
+		return Message{Kind: Result, Args: []string{pos.String(), "HIT"}}
+	}
+	return Message{Kind: Result, Args: []string{pos.String(), "MISS"}}
+}
+
+// ParseResult returns the cell, result and, if it was sunk, the revealed ship
+// of a RESULT message
+func ParseResult(m Message) (game.Position, game.ShotResult, *game.Ship, error) {
+	if m.Kind != Result || len(m.Args) < 2 {
+		return game.Position{}, 0, nil, fmt.Errorf("%w: expected RESULT, got %q", ErrMalformed, m)
+	}
+
+	pos, err := game.ParsePosition(m.Args[0])
+	if err != nil {
+		return game.Position{}, 0, nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	switch strings.ToUpper(m.Args[1]) {
+	case "MISS":
+		return pos, game.ShotMiss, nil, nil
+	case "HIT":
+		return pos, game.ShotHit, nil, nil
+	case "SUNK":
+		if len(m.Args) < 4 {
+			return game.Position{}, 0, nil, fmt.Errorf("%w: SUNK needs the ship's cells and name", ErrMalformed)
+		}
+		var positions []game.Position
+		for _, cell := range strings.Split(m.Args[2], ",") {
+			p, err := game.ParsePosition(cell)
+			if err != nil {
+				return game.Position{}, 0, nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+			}
+			positions = append(positions, p)
+		}
+		name := strings.Join(m.Args[3:], " ")
+		return pos, game.ShotSunk, game.NewSunkShip(name, positions), nil
+	}
+	return game.Position{}, 0, nil, fmt.Errorf("%w: unknown result %q", ErrMalformed, m.Args[1])
+}
+
+// ErrorMessage reports a protocol error before hanging up
+func ErrorMessage(reason string) Message {
+	return Message{Kind: Error, Args: strings.Fields(reason)}
+}
+
+// RevealMessage reveals the sender's fleet layout and nonce after the game
+func RevealMessage(reveal game.FleetReveal) (Message, error) {
+	data, err := json.Marshal(reveal)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Kind: Reveal, Args: []string{base64.StdEncoding.EncodeToString(data)}}, nil
+}
+
+// ParseReveal returns the fleet layout and nonce of a REVEAL message
+func ParseReveal(m Message) (game.FleetReveal, error) {
+	var reveal game.FleetReveal
+	if m.Kind != Reveal || len(m.Args) != 1 {
+		return reveal, fmt.Errorf("%w: expected REVEAL, got %q", ErrMalformed, m)
+	}
+	if err := unmarshalArg(m.Args[0], &reveal); err != nil {
+		return reveal, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return reveal, nil
+}
+
+// unmarshalArg decodes a field holding base64-encoded JSON into v
+func unmarshalArg(arg string, v any) error {
+	data, err := base64.StdEncoding.DecodeString(arg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"battleship/game"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ProfileVersion is the version of the profile file format. Version 1 kept
+// achievements as plain flags.
+const ProfileVersion = 2
+
+// Profile is everything remembered about the player between runs
+type Profile struct {
+	Version      int          `json:"version"`
+	Achievements Achievements `json:"achievements"`
+	Stats        *Statistics  `json:"stats"`
+	path         string       // File the profile is saved to, empty if it cannot be saved
+}
+
+// profilePaths returns the location of the profile file and of the
+// achievements file it replaced
+func profilePaths() (profile, legacy string, err error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(homeDir, ".battleship_profile.json"), filepath.Join(homeDir, ".battleship_achievements.json"), nil
+}
+
+// LoadProfile loads the player's profile, carrying over the achievements of
+// older versions that only kept ~/.battleship_achievements.json
+func LoadProfile() *Profile {
+	path, legacy, err := profilePaths()
+	if err != nil {
+		return newProfile("")
+	}
+	return loadProfile(path, legacy)
+}
+
+// newProfile creates an empty profile saved to path
+func newProfile(path string) *Profile {
+	return &Profile{
+		Version:      ProfileVersion,
+		Achievements: Achievements{},
+		Stats:        &Statistics{},
+		path:         path,
+	}
+}
+
+// loadProfile reads the profile at path. If there is none yet it is created
+// from the achievements file at legacy, which is left untouched.
+func loadProfile(path, legacy string) *Profile {
+	p := newProfile(path)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if data, err := os.ReadFile(legacy); err == nil {
+			if achievements, err := migrateAchievements(data); err == nil {
+				p.Achievements = achievements
+				p.Save()
+			}
+		}
+		return p
+	}
+
+	var stored struct {
+		Version      int             `json:"version"`
+		Achievements json.RawMessage `json:"achievements"`
+		Stats        *Statistics     `json:"stats"`
+	}
+	if err != nil || json.Unmarshal(data, &stored) != nil {
+		return p
+	}
+
+	// Anything missing from a hand-edited file is left empty
+	if stored.Stats != nil {
+		p.Stats = stored.Stats
+	}
+	if len(stored.Achievements) > 0 {
+		if stored.Version < 2 {
+			achievements, err := migrateAchievements(stored.Achievements)
+			if err != nil {
+				return newProfile(path)
+			}
+			p.Achievements = achievements
+		} else if json.Unmarshal(stored.Achievements, &p.Achievements) != nil {
+			return newProfile(path)
+		}
+	}
+	if p.Achievements == nil {
+		p.Achievements = Achievements{}
+	}
+	return p
+}
+
+// Save writes the profile to disk
+func (p *Profile) Save() error {
+	if p.path == "" {
+		return errors.New("no profile location")
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.path, data, 0644)
+}
+
+// RecordGame adds a finished game to the statistics, unlocks any achievements
+// it earned and saves the profile. It returns the newly unlocked achievements.
+func (p *Profile) RecordGame(g *game.Game) []Achievement {
+	// Statistics and achievements only cover games against Captain Claude
+	if g.Phase != game.GameOverPhase || g.Mode != game.VsComputer {
+		return nil
+	}
+
+	at := time.Now()
+	p.Stats.Record(g, at)
+	unlocked := p.Achievements.CheckAndUnlock(g, at)
+	p.Save()
+	return unlocked
+}
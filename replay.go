@@ -0,0 +1,150 @@
+package main
+
+import (
+	"battleship/game"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// replaySession holds the state of the replay viewer
+type replaySession struct {
+	replay *game.Replay
+	turn   int // Turns shown so far, 0 before the first shot
+}
+
+// replayDir returns the directory finished games are recorded in, next to
+// the profile file
+func replayDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".battleship_replays"), nil
+}
+
+// SaveReplay records a finished game in the replay directory and returns the
+// path of the replay file
+func SaveReplay(g *game.Game, at time.Time) (string, error) {
+	replay, err := game.NewReplay(g)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := replayDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	// Games can end within the same second, so each gets a unique suffix
+	file, err := os.CreateTemp(dir, at.Format("2006-01-02_15-04-05")+"_*.json")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if err := replay.Save(file); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// ExportNotation writes a finished game in game notation, next to the replay
+// it was recorded in, and returns the path of the file
+func ExportNotation(g *game.Game, replayPath string) (string, error) {
+	if replayPath == "" {
+		return "", errors.New("the game was not recorded")
+	}
+	replay, err := game.NewReplay(g)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := strings.TrimSuffix(replayPath, filepath.Ext(replayPath)) + ".txt"
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return filePath, replay.WriteNotation(file)
+}
+
+// loadReplayFile reads a replay file, or a game in game notation
+func loadReplayFile(path string) (*game.Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if text := strings.TrimSpace(string(data)); strings.HasPrefix(text, game.NotationHeader) {
+		return game.ParseNotation(strings.NewReader(text))
+	}
+	return game.LoadReplay(bytes.NewReader(data))
+}
+
+// runReplay runs the replay subcommand, stepping through a recorded game or
+// one imported from game notation
+func runReplay(args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New("usage: battleship replay <replay or notation file>")
+	}
+
+	replay, err := loadReplayFile(args[0])
+	if err != nil {
+		return fmt.Errorf("could not load replay: %w", err)
+	}
+
+	fmt.Fprintf(out, "Replaying %s...\n", args[0])
+	p := tea.NewProgram(ReplayModel(replay), tea.WithAltScreen())
+	_, err = p.Run()
+	return err
+}
+
+// ReplayModel creates a model that steps through a recorded game with both
+// fleets revealed, starting before the first shot
+func ReplayModel(replay *game.Replay) Model {
+	m := InitialModel(0, false, nil)
+	m.replay = &replaySession{replay: replay}
+	m.game = replay.GameAt(0)
+	m.showHelp = true
+	m.cursorRow = -1
+	m.cursorCol = -1
+	return m
+}
+
+// handleReplayKey handles keys in the replay viewer
+func (m Model) handleReplayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	turn := m.replay.turn
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+	case "h":
+		m.showHelp = !m.showHelp
+	case "right", "l", " ", "enter":
+		turn++
+	case "left", "backspace":
+		turn--
+	case "home", "g":
+		turn = 0
+	case "end", "G":
+		turn = m.replay.replay.Turns()
+	}
+
+	turn = max(0, min(turn, m.replay.replay.Turns()))
+	if turn != m.replay.turn {
+		m.replay = &replaySession{replay: m.replay.replay, turn: turn}
+		m.game = m.replay.replay.GameAt(turn)
+	}
+	return m, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"battleship/game"
+	"testing"
+	"time"
+)
+
+func TestSaveReplayKeepsGamesFromTheSameSecond(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	settings := game.DefaultSettings()
+	settings.Mode = game.ComputerVsComputer
+	settings.PlayerStrategy = game.Easy.String()
+
+	at := time.Date(2026, 10, 17, 20, 15, 4, 0, time.UTC)
+	paths := map[string]bool{}
+	for seed := int64(1); seed <= 3; seed++ {
+		g, err := game.NewGameWithSeed(settings, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for g.Phase != game.GameOverPhase {
+			g.AutoPlayerAttack()
+			g.ComputerAttack()
+		}
+
+		path, err := SaveReplay(g, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if paths[path] {
+			t.Fatalf("two replays were saved to %s", path)
+		}
+		paths[path] = true
+
+		r, err := loadReplayFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Seed != seed {
+			t.Errorf("%s holds the game with seed %d, want %d", path, r.Seed, seed)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"battleship/game"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// savePath returns the location of the autosave file, next to the profile file
+func savePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".battleship_save.json"), nil
+}
+
+// Autosave writes an in-progress game to disk, or removes the autosave once the game is over
+func Autosave(g *game.Game) error {
+	// A network game cannot be resumed once the connection is gone
+	if g.Phase == game.MainMenuPhase || g.Mode == game.Network {
+		return nil
+	}
+	if g.Phase == game.GameOverPhase {
+		return RemoveAutosave()
+	}
+
+	filePath, err := savePath()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return g.Save(file)
+}
+
+// LoadAutosave restores the last autosaved game
+func LoadAutosave() (*game.Game, error) {
+	filePath, err := savePath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.New("no saved game to continue")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	g, err := game.LoadGame(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not load saved game: %w", err)
+	}
+	return g, nil
+}
+
+// RemoveAutosave deletes the autosave file if there is one
+func RemoveAutosave() error {
+	filePath, err := savePath()
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"battleship/game"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// simulationResult holds the outcome of a batch of computer-vs-computer games
+type simulationResult struct {
+	wins       [2]int
+	shotsToWin [2][]int
+	elapsed    time.Duration
+}
+
+// runSimulate runs the simulate subcommand, playing games between two
+// strategies without a terminal UI
+func runSimulate(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	games := fs.Int("games", 100, "number of games to play")
+	first := fs.String("a", "hard", "strategy of the first computer player")
+	second := fs.String("b", "normal", "strategy of the second computer player")
+	boardSize := fs.String("size", "10", "board size, e.g. 10 or 8x12")
+	salvo := fs.Bool("salvo", false, "play in salvo mode")
+	hitCounts := fs.Bool("hitcounts", false, "in salvo mode, announce only how many shots of each salvo hit")
+	salvoRule := fs.String("salvorule", "one per ship", "in salvo mode, shots per salvo: one per ship, fixed N, largest ship or chain")
+	fleetName := fs.String("fleet", game.DefaultFleet.Name, "built-in fleet name or path to a fleet JSON file")
+	noTouch := fs.Bool("notouch", false, "ships may not touch, not even diagonally")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "seed for the first game, later games use seed+1, seed+2, ...")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	a, err := findStrategy(*first)
+	if err != nil {
+		return err
+	}
+	b, err := findStrategy(*second)
+	if err != nil {
+		return err
+	}
+	if *games <= 0 {
+		return fmt.Errorf("games must be positive, got %d", *games)
+	}
+	fleet, err := resolveFleet(*fleetName)
+	if err != nil {
+		return err
+	}
+	rows, cols, err := game.ParseBoardSize(*boardSize)
+	if err != nil {
+		return err
+	}
+	rule, err := game.ParseSalvoRule(*salvoRule)
+	if err != nil {
+		return err
+	}
+	settings := game.Settings{
+		Rows:          rows,
+		Cols:          cols,
+		Fleet:         fleet,
+		Rules:         game.PlacementRules{NoTouch: *noTouch},
+		Mode:          game.ComputerVsComputer,
+		SalvoMode:     *salvo,
+		HitCountsOnly: *hitCounts,
+		SalvoRule:     rule,
+	}
+	if err := game.CheckBoardSize(rows, cols, fleet, settings.Rules); err != nil {
+		return err
+	}
+	if settings.HitCountsOnly && !settings.SalvoMode {
+		return errors.New("-hitcounts needs -salvo")
+	}
+	if settings.HitCountsOnly && rule.Kind == game.ChainShots {
+		return errors.New("-hitcounts cannot be used with a chain")
+	}
+
+	result, err := simulate([2]string{a, b}, *games, settings, *seed)
+	if err != nil {
+		return err
+	}
+	printSimulation(out, [2]string{a, b}, *games, settings, result)
+	return nil
+}
+
+// findStrategy returns the registered strategy name matching name, ignoring case
+func findStrategy(name string) (string, error) {
+	for _, registered := range game.StrategyNames() {
+		if strings.EqualFold(registered, name) {
+			return registered, nil
+		}
+	}
+	return "", fmt.Errorf("unknown strategy %q, choose from %s", name, strings.Join(game.StrategyNames(), ", "))
+}
+
+// simulate plays the given number of games between two strategies with the
+// given board, fleet and salvo settings. The players swap sides every game so
+// neither always fires first.
+func simulate(players [2]string, games int, settings game.Settings, seed int64) (simulationResult, error) {
+	var result simulationResult
+	start := time.Now()
+
+	for i := 0; i < games; i++ {
+		// first is the index into players of whoever plays the player side
+		first := i % 2
+		settings.PlayerStrategy = players[first]
+		settings.Strategy = players[1-first]
+		g, err := game.NewGameWithSeed(settings, seed+int64(i))
+		if err != nil {
+			return result, err
+		}
+
+		for g.Phase != game.GameOverPhase {
+			if g.Phase == game.PlayerTurnPhase {
+				g.AutoPlayerAttack()
+			} else {
+				g.ComputerAttack()
+			}
+		}
+
+		winner := first
+		shooter := game.PlayerSide
+		if g.Winner != "Player" {
+			winner = 1 - first
+			shooter = game.ComputerSide
+		}
+		result.wins[winner]++
+		result.shotsToWin[winner] = append(result.shotsToWin[winner], g.Shots.ShotsFired(shooter))
+	}
+
+	result.elapsed = time.Since(start)
+	return result, nil
+}
+
+// printSimulation writes a summary of a simulation run
+func printSimulation(out io.Writer, players [2]string, games int, settings game.Settings, result simulationResult) {
+	mode := "single shot"
+	if settings.SalvoMode {
+		mode = "salvo, " + strings.ToLower(settings.SalvoRule.String())
+	}
+	if settings.HitCountsOnly {
+		mode += " announcing hit counts only"
+	}
+	if settings.Rules.NoTouch {
+		mode += ", ships not touching"
+	}
+
+	fmt.Fprintf(out, "%d games on %dx%d (%s), %s\n\n", games, settings.Rows, settings.Cols, settings.Fleet.Name, mode)
+
+	labels := [2]string{"A (" + players[0] + ")", "B (" + players[1] + ")"}
+	for i, label := range labels {
+		fmt.Fprintf(out, "%-12s wins %5d (%5.1f%%)  mean shots to win: %s\n",
+			label, result.wins[i], 100*float64(result.wins[i])/float64(games), meanShots(result.shotsToWin[i]))
+	}
+
+	// Distribution of shots to win in buckets of ten
+	const bucket = 10
+	for i, label := range labels {
+		if len(result.shotsToWin[i]) == 0 {
+			continue
+		}
+
+		counts := map[int]int{}
+		low, high, most := -1, 0, 0
+		for _, n := range result.shotsToWin[i] {
+			b := n / bucket * bucket
+			counts[b]++
+			if low < 0 || b < low {
+				low = b
+			}
+			if b > high {
+				high = b
+			}
+			if counts[b] > most {
+				most = counts[b]
+			}
+		}
+
+		fmt.Fprintf(out, "\nShots to win for %s\n", label)
+		for b := low; b <= high; b += bucket {
+			width := counts[b] * 40 / most
+			fmt.Fprintf(out, "  %3d-%-3d %s %d\n", b, b+bucket-1, strings.Repeat("█", width), counts[b])
+		}
+	}
+
+	fmt.Fprintf(out, "\nCompleted in %s\n", result.elapsed.Round(time.Millisecond))
+}
+
+// meanShots formats the mean of a list of shot counts
+func meanShots(shots []int) string {
+	if len(shots) == 0 {
+		return "-"
+	}
+	total := 0
+	for _, n := range shots {
+		total += n
+	}
+	return fmt.Sprintf("%.1f", float64(total)/float64(len(shots)))
+}
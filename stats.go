@@ -0,0 +1,135 @@
+package main
+
+import (
+	"battleship/game"
+	"fmt"
+	"time"
+)
+
+// maxRecentGames is how many games the statistics keep a record of
+const maxRecentGames = 10
+
+// Statistics are the player's results against Captain Claude
+type Statistics struct {
+	Played        int                `json:"played"`
+	Wins          int                `json:"wins"`
+	Losses        int                `json:"losses"`
+	ShotsToWin    int                `json:"shots_to_win"`  // Total shots fired in won games
+	BestAccuracy  float64            `json:"best_accuracy"` // Best accuracy in a won game
+	CurrentStreak int                `json:"current_streak"`
+	LongestStreak int                `json:"longest_streak"`
+	ByDifficulty  map[string]*Record `json:"by_difficulty"`
+	ByBoardSize   map[string]*Record `json:"by_board_size"`
+	ByFiringMode  map[string]*Record `json:"by_firing_mode"`
+	Recent        []GameRecord       `json:"recent"` // Most recent first
+}
+
+// Record is a tally of wins and losses
+type Record struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+}
+
+// GameRecord summarises one finished game
+type GameRecord struct {
+	Date       time.Time `json:"date"`
+	Won        bool      `json:"won"`
+	Difficulty string    `json:"difficulty"`
+	Rows       int       `json:"rows"`
+	Cols       int       `json:"cols"`
+	Fleet      string    `json:"fleet"`
+	Salvo      bool      `json:"salvo"`
+	HitCounts  bool      `json:"hit_counts,omitempty"` // Salvos announced only how many shots hit
+	Shots      int       `json:"shots"`
+	Accuracy   float64   `json:"accuracy"`
+}
+
+// Played returns the number of games in the tally
+func (r *Record) Played() int {
+	return r.Wins + r.Losses
+}
+
+// WinRate returns the fraction of games won
+func (r *Record) WinRate() float64 {
+	if r.Played() == 0 {
+		return 0
+	}
+	return float64(r.Wins) / float64(r.Played())
+}
+
+// BoardSize returns the board dimensions of the game, e.g. "8x12"
+func (r GameRecord) BoardSize() string {
+	return fmt.Sprintf("%dx%d", r.Rows, r.Cols)
+}
+
+// FiringMode returns "Salvo", "Salvo (hit counts)" or "Single shot"
+func (r GameRecord) FiringMode() string {
+	if r.Salvo && r.HitCounts {
+		return "Salvo (hit counts)"
+	}
+	if r.Salvo {
+		return "Salvo"
+	}
+	return "Single shot"
+}
+
+// Record adds a finished game against Captain Claude, played at the given time
+func (s *Statistics) Record(g *game.Game, at time.Time) {
+	record := GameRecord{
+		Date:       at,
+		Won:        g.Winner == "Player",
+		Difficulty: g.ComputerStrategy,
+		Rows:       g.Rows,
+		Cols:       g.Cols,
+		Fleet:      g.Fleet.Name,
+		Salvo:      g.SalvoMode,
+		HitCounts:  g.SalvoMode && g.HitCountsOnly,
+		Shots:      g.Shots.ShotsFired(game.PlayerSide),
+		Accuracy:   g.Shots.Accuracy(game.PlayerSide),
+	}
+
+	s.Played++
+	if record.Won {
+		s.Wins++
+		s.ShotsToWin += record.Shots
+		s.BestAccuracy = max(s.BestAccuracy, record.Accuracy)
+		s.CurrentStreak++
+		s.LongestStreak = max(s.LongestStreak, s.CurrentStreak)
+	} else {
+		s.Losses++
+		s.CurrentStreak = 0
+	}
+
+	s.ByDifficulty = tally(s.ByDifficulty, record.Difficulty, record.Won)
+	s.ByBoardSize = tally(s.ByBoardSize, record.BoardSize(), record.Won)
+	s.ByFiringMode = tally(s.ByFiringMode, record.FiringMode(), record.Won)
+
+	s.Recent = append([]GameRecord{record}, s.Recent...)
+	if len(s.Recent) > maxRecentGames {
+		s.Recent = s.Recent[:maxRecentGames]
+	}
+}
+
+// tally adds a game to the record under key, creating the map if needed
+func tally(records map[string]*Record, key string, won bool) map[string]*Record {
+	if records == nil {
+		records = map[string]*Record{}
+	}
+	if records[key] == nil {
+		records[key] = &Record{}
+	}
+	if won {
+		records[key].Wins++
+	} else {
+		records[key].Losses++
+	}
+	return records
+}
+
+// AverageShotsToWin returns the mean number of shots fired in won games
+func (s *Statistics) AverageShotsToWin() float64 {
+	if s.Wins == 0 {
+		return 0
+	}
+	return float64(s.ShotsToWin) / float64(s.Wins)
+}
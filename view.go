@@ -3,9 +3,11 @@ package main
 import (
 	"battleship/game"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
 )
 
 var (
@@ -50,10 +52,22 @@ var (
 			Background(darkBlue).
 			Bold(true)
 
+	sunkStyle = cellStyle.Copy().
+			Foreground(hitRed).
+			Background(darkBlue)
+
 	missStyle = cellStyle.Copy().
 			Foreground(missWhite).
 			Background(darkBlue)
 
+	uncertainStyle = cellStyle.Copy().
+			Foreground(lipgloss.Color("#FF8888")).
+			Background(darkBlue)
+
+	blockedStyle = cellStyle.Copy().
+			Foreground(shipGray).
+			Background(darkBlue)
+
 	cursorStyle = cellStyle.Copy().
 			Foreground(cursorYellow).
 			Background(darkBlue).
@@ -69,6 +83,13 @@ var (
 			Background(darkBlue).
 			Bold(true)
 
+	salvoHitStyle = lipgloss.NewStyle().
+			Foreground(hitRed).
+			Bold(true)
+
+	salvoMissStyle = lipgloss.NewStyle().
+			Foreground(missWhite)
+
 	messageStyle = lipgloss.NewStyle().
 			Foreground(successGreen).
 			Bold(true).
@@ -101,6 +122,20 @@ var (
 				BorderForeground(cursorYellow).
 				Padding(0, 3)
 
+	menuMessageStyle = lipgloss.NewStyle().
+				Foreground(hitRed).
+				Bold(true).
+				Padding(0, 4)
+
+	unlockedAchievementStyle = lipgloss.NewStyle().
+					Foreground(lipgloss.Color("#FFD700")).
+					Bold(true).
+					Padding(0, 4)
+
+	lockedAchievementStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#666666")).
+				Padding(0, 4)
+
 	asciiArtStyle = lipgloss.NewStyle().
 			Foreground(oceanBlue).
 			Align(lipgloss.Center)
@@ -113,9 +148,21 @@ var (
 )
 
 func renderGame(m Model) string {
+	if m.replay != nil {
+		return renderReplay(m)
+	}
+	if m.showStats {
+		return renderStatistics(m)
+	}
+	if m.showAchievementsMenu {
+		return renderAchievements(m)
+	}
 	if m.game.Phase == game.MainMenuPhase {
 		return renderMainMenu(m)
 	}
+	if m.game.Phase == game.HandoffPhase {
+		return renderHandoff(m)
+	}
 
 	var sb strings.Builder
 
@@ -136,9 +183,9 @@ func renderGame(m Model) string {
 
 	// Render boards side by side
 	switch m.game.Phase {
-	case game.PlacementPhase:
+	case game.PlacementPhase, game.FleetReviewPhase:
 		sb.WriteString(renderPlacementBoard(m))
-	case game.PlayerTurnPhase, game.ComputerTurnPhase:
+	case game.PlayerTurnPhase, game.ComputerTurnPhase, game.WaitingPhase:
 		sb.WriteString(renderBattleBoards(m))
 	case game.GameOverPhase:
 		sb.WriteString(renderGameOver(m))
@@ -165,19 +212,57 @@ func renderMainMenu(m Model) string {
 	sb.WriteString(asciiArtStyle.Render(menuBattleshipsArt))
 	sb.WriteString("\n\n")
 
+	// Mode selection
+	modeText := "◀  Opponent: Captain Claude  ▶"
+	if m.settings.Mode == game.HotSeat {
+		modeText = "◀  Opponent: Hot Seat (2 players)  ▶"
+	}
+	if m.menuSelection == menuMode {
+		sb.WriteString(selectedMenuItemStyle.Render(modeText))
+	} else {
+		sb.WriteString(menuItemStyle.Render(modeText))
+	}
+	sb.WriteString("\n\n")
+
 	// Board size selection
-	boardSizeText := fmt.Sprintf("◀  Board Size: %dx%d  ▶", m.selectedBoardSize, m.selectedBoardSize)
-	if m.menuSelection == 0 {
+	rows, cols := m.boardSize()
+	boardSizeText := fmt.Sprintf("◀  Board Size: %dx%d  ▶", rows, cols)
+	if m.editingSize {
+		boardSizeText = fmt.Sprintf("Board Size: %s█  (rows x columns, Enter to confirm, Esc to cancel)", m.sizeInput)
+	} else if m.selectedBoardSize == customBoardSize {
+		boardSizeText = fmt.Sprintf("◀  Board Size: Custom %dx%d (Enter to change)  ▶", rows, cols)
+	}
+	if m.menuSelection == menuBoardSize {
 		sb.WriteString(selectedMenuItemStyle.Render(boardSizeText))
 	} else {
 		sb.WriteString(menuItemStyle.Render(boardSizeText))
 	}
 	sb.WriteString("\n\n")
 
+	// Fleet selection
+	fleetText := fmt.Sprintf("◀  Fleet: %s (%d ships)  ▶", m.settings.Fleet.Name, len(m.settings.Fleet.Ships))
+	if m.menuSelection == menuFleet {
+		sb.WriteString(selectedMenuItemStyle.Render(fleetText))
+	} else {
+		sb.WriteString(menuItemStyle.Render(fleetText))
+	}
+	sb.WriteString("\n\n")
+
+	// Ship spacing rule
+	touchText := "◀  Ships Touching: Allowed  ▶"
+	if m.settings.Rules.NoTouch {
+		touchText = "◀  Ships Touching: Not allowed  ▶"
+	}
+	if m.menuSelection == menuRules {
+		sb.WriteString(selectedMenuItemStyle.Render(touchText))
+	} else {
+		sb.WriteString(menuItemStyle.Render(touchText))
+	}
+	sb.WriteString("\n\n")
+
 	// Difficulty selection
-	difficultyNames := []string{"Easy", "Normal", "Hard"}
-	difficultyText := fmt.Sprintf("◀  Difficulty: %s  ▶", difficultyNames[m.selectedDifficulty])
-	if m.menuSelection == 1 {
+	difficultyText := fmt.Sprintf("◀  Difficulty: %s  ▶", m.settings.Strategy)
+	if m.menuSelection == menuDifficulty {
 		sb.WriteString(selectedMenuItemStyle.Render(difficultyText))
 	} else {
 		sb.WriteString(menuItemStyle.Render(difficultyText))
@@ -186,38 +271,119 @@ func renderMainMenu(m Model) string {
 
 	// Salvo mode selection
 	salvoText := "◀  Salvo Mode: Off  ▶"
-	if m.selectedSalvoMode {
+	if m.settings.HitCountsOnly {
+		salvoText = "◀  Salvo Mode: Hit Counts Only  ▶"
+	} else if m.settings.SalvoMode {
 		salvoText = "◀  Salvo Mode: On  ▶"
 	}
-	if m.menuSelection == 2 {
+	if m.menuSelection == menuSalvo {
 		sb.WriteString(selectedMenuItemStyle.Render(salvoText))
 	} else {
 		sb.WriteString(menuItemStyle.Render(salvoText))
 	}
 	sb.WriteString("\n\n")
 
+	// Salvo shot count selection
+	salvoRuleText := fmt.Sprintf("◀  Salvo Shots: %s  ▶", m.settings.SalvoRule)
+	if m.menuSelection == menuSalvoRule {
+		sb.WriteString(selectedMenuItemStyle.Render(salvoRuleText))
+	} else {
+		sb.WriteString(menuItemStyle.Render(salvoRuleText))
+	}
+	sb.WriteString("\n\n")
+
 	// Start game
-	if m.menuSelection == 3 {
+	if m.menuSelection == menuStart {
 		sb.WriteString(selectedMenuItemStyle.Render("▶  Start New Game"))
 	} else {
 		sb.WriteString(menuItemStyle.Render("▶  Start New Game"))
 	}
 	sb.WriteString("\n\n")
 
+	// Continue saved game
+	if m.menuSelection == menuContinue {
+		sb.WriteString(selectedMenuItemStyle.Render("↺  Continue"))
+	} else {
+		sb.WriteString(menuItemStyle.Render("↺  Continue"))
+	}
+	sb.WriteString("\n\n")
+
+	// Achievements
+	if m.menuSelection == menuAchievements {
+		sb.WriteString(selectedMenuItemStyle.Render("★  Achievements"))
+	} else {
+		sb.WriteString(menuItemStyle.Render("★  Achievements"))
+	}
+	sb.WriteString("\n\n")
+
+	// Statistics
+	if m.menuSelection == menuStats {
+		sb.WriteString(selectedMenuItemStyle.Render("≡  Statistics"))
+	} else {
+		sb.WriteString(menuItemStyle.Render("≡  Statistics"))
+	}
+	sb.WriteString("\n\n")
+
 	// Quit
-	if m.menuSelection == 4 {
+	if m.menuSelection == menuQuit {
 		sb.WriteString(selectedMenuItemStyle.Render("✕  Quit"))
 	} else {
 		sb.WriteString(menuItemStyle.Render("✕  Quit"))
 	}
 	sb.WriteString("\n\n")
 
+	if m.menuMessage != "" {
+		sb.WriteString(menuMessageStyle.Render(m.menuMessage))
+		sb.WriteString("\n")
+	}
+
 	sb.WriteString("\n")
 	sb.WriteString(helpStyle.Render("Use ↑/↓ to navigate, ←/→ to change options, Enter to select"))
 
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, sb.String())
 }
 
+// renderHandoff renders the full-screen curtain shown while a hot-seat game
+// changes hands, so neither player sees the other's fleet
+func renderHandoff(m Model) string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render("⚓ BATTLESHIP ⚓"))
+	sb.WriteString("\n\n")
+
+	if m.game.LastMessage != "" {
+		sb.WriteString(messageStyle.Render(m.game.LastMessage))
+		sb.WriteString("\n\n")
+	}
+
+	curtainStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(cursorYellow).
+		Padding(1, 4).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(cursorYellow)
+
+	next := m.game.SideName(m.game.Active)
+	sb.WriteString(curtainStyle.Render(fmt.Sprintf("Pass the keyboard to %s\n\n%s, press Enter when ready", next, next)))
+	sb.WriteString("\n")
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, sb.String())
+}
+
+// fleetTitle returns the header for a side's own fleet
+func fleetTitle(m Model, side game.Side) string {
+	if m.game.Mode == game.HotSeat {
+		return m.game.SideName(side) + "'s Fleet"
+	}
+	if side == game.PlayerSide {
+		return "Your Fleet"
+	}
+	if m.game.Mode == game.Network {
+		return "Opponent's Fleet"
+	}
+	return "Captain Claude's Fleet"
+}
+
 func renderAnimation(m Model) string {
 	var animationArt string
 	var animationStyle lipgloss.Style
@@ -242,7 +408,7 @@ func renderPhaseMessage(m Model) string {
 	msg := ""
 
 	switch m.game.Phase {
-	case game.PlacementPhase:
+	case game.PlacementPhase, game.FleetReviewPhase:
 		ship := m.game.GetCurrentShipForPlacement()
 		if ship != nil {
 			orientation := "Horizontal"
@@ -251,6 +417,26 @@ func renderPhaseMessage(m Model) string {
 			}
 			msg = fmt.Sprintf("Place your %s (Length: %d) - Orientation: %s",
 				ship.Name, ship.Length, orientation)
+			if m.moving != nil {
+				msg = fmt.Sprintf("Moving your %s (Length: %d) - Orientation: %s - Space to put it down, M to cancel",
+					m.moving.Name, m.moving.Length, orientation)
+			}
+			if m.game.Mode == game.HotSeat {
+				msg = m.game.SideName(m.game.Active) + ": " + msg
+			}
+		}
+		if m.game.Phase == game.FleetReviewPhase {
+			msg = "Fleet complete! Adjust it if you like, then press C to confirm"
+			if m.moving != nil {
+				msg = fmt.Sprintf("Moving your %s - Space to put it down, M to cancel", m.moving.Name)
+			}
+			if m.game.Mode == game.HotSeat {
+				msg = m.game.SideName(m.game.Active) + ": " + msg
+			}
+		}
+		if m.game.LastMessage != "" {
+			// e.g. why the fleet could not be randomized
+			msg += "\n" + m.game.LastMessage
 		}
 	case game.PlayerTurnPhase:
 		if m.game.SalvoMode {
@@ -261,13 +447,16 @@ func renderPhaseMessage(m Model) string {
 			msg = "Your turn! Select a target and fire!"
 		}
 	case game.ComputerTurnPhase:
+		if m.game.Mode == game.Network {
+			return messageStyle.Render(m.game.LastMessage) + "\n" + claudeThinkingStyle.Render("Your opponent is taking aim...")
+		}
 		claudeMsg := m.game.ClaudeThinking + "..."
 		return claudeThinkingStyle.Render("Captain Claude is " + claudeMsg)
 	case game.GameOverPhase:
 		msg = fmt.Sprintf("Game Over! %s wins!", m.game.Winner)
 	}
 
-	if m.game.LastMessage != "" && m.game.Phase != game.PlacementPhase {
+	if m.game.LastMessage != "" && !m.game.IsPlacing() {
 		msg = m.game.LastMessage
 	}
 
@@ -276,47 +465,45 @@ func renderPhaseMessage(m Model) string {
 
 func renderPlacementBoard(m Model) string {
 	var sb strings.Builder
+	board := m.game.Board(m.game.Active)
 
-	sb.WriteString(headerStyle.Render("Your Fleet"))
+	sb.WriteString(headerStyle.Render(fleetTitle(m, m.game.Active)))
 	sb.WriteString("\n\n")
 
 	// Render column headers
 	sb.WriteString("    ")
-	for col := 0; col < m.game.BoardSize; col++ {
+	for col := 0; col < m.game.Cols; col++ {
 		sb.WriteString(fmt.Sprintf(" %c ", 'A'+col))
 	}
 	sb.WriteString("\n")
 
 	// Render board
-	for row := 0; row < m.game.BoardSize; row++ {
+	for row := 0; row < m.game.Rows; row++ {
 		// Row number
 		sb.WriteString(fmt.Sprintf("%2d  ", row+1))
 
-		for col := 0; col < m.game.BoardSize; col++ {
+		for col := 0; col < m.game.Cols; col++ {
 			pos := game.Position{Row: row, Col: col}
-			cell := m.game.PlayerBoard.GetCell(pos)
+			cell := board.GetCell(pos)
 
 			// Check if this is a preview position for the current ship
 			isPreview := false
 			isCursor := row == m.cursorRow && col == m.cursorCol
 
 			if isCursor {
-				ship := m.game.GetCurrentShipForPlacement()
-				if ship != nil {
-					if m.game.PlayerBoard.CanPlaceShip(pos, ship.Length, m.shipOrientation) {
-						// Show preview
-						for i := 0; i < ship.Length; i++ {
-							previewRow := row
-							previewCol := col
-							if m.shipOrientation == game.Horizontal {
-								previewCol += i
-							} else {
-								previewRow += i
-							}
-							if previewRow == row && previewCol == col {
-								isPreview = true
-								break
-							}
+				if length, fits := placementPreview(m, board, pos); fits {
+					// Show preview
+					for i := 0; i < length; i++ {
+						previewRow := row
+						previewCol := col
+						if m.shipOrientation == game.Horizontal {
+							previewCol += i
+						} else {
+							previewRow += i
+						}
+						if previewRow == row && previewCol == col {
+							isPreview = true
+							break
 						}
 					}
 				}
@@ -324,28 +511,41 @@ func renderPlacementBoard(m Model) string {
 
 			// Check if any upcoming preview cell matches this position
 			if !isPreview && !isCursor {
-				ship := m.game.GetCurrentShipForPlacement()
-				if ship != nil {
-					cursorPos := game.Position{Row: m.cursorRow, Col: m.cursorCol}
-					if m.game.PlayerBoard.CanPlaceShip(cursorPos, ship.Length, m.shipOrientation) {
-						for i := 0; i < ship.Length; i++ {
-							previewRow := m.cursorRow
-							previewCol := m.cursorCol
-							if m.shipOrientation == game.Horizontal {
-								previewCol += i
-							} else {
-								previewRow += i
-							}
-							if previewRow == row && previewCol == col {
-								isPreview = true
-								break
-							}
+				cursorPos := game.Position{Row: m.cursorRow, Col: m.cursorCol}
+				if length, fits := placementPreview(m, board, cursorPos); fits {
+					for i := 0; i < length; i++ {
+						previewRow := m.cursorRow
+						previewCol := m.cursorCol
+						if m.shipOrientation == game.Horizontal {
+							previewCol += i
+						} else {
+							previewRow += i
+						}
+						if previewRow == row && previewCol == col {
+							isPreview = true
+							break
 						}
 					}
 				}
 			}
 
-			cellStr := renderCell(cell, isCursor, isPreview, false)
+			// Cells next to a placed ship are off limits when ships may not touch
+			if board.IsBlocked(pos) && !isCursor && !isPreview {
+				sb.WriteString(blockedStyle.Render(" · "))
+				continue
+			}
+
+			// The ship being moved stays dimmed until it is put down
+			if m.moving != nil && board.ShipAt(pos) == m.moving {
+				if isPreview {
+					cell = game.Empty
+				} else if !isCursor {
+					sb.WriteString(blockedStyle.Render(" █ "))
+					continue
+				}
+			}
+
+			cellStr := renderCell(cell, isCursor, isPreview, true)
 			sb.WriteString(cellStr)
 		}
 		sb.WriteString("\n")
@@ -354,11 +554,81 @@ func renderPlacementBoard(m Model) string {
 	return boardStyle.Render(sb.String())
 }
 
+// placementPreview returns the length of the ship being placed or moved, and
+// whether it fits at pos
+func placementPreview(m Model, board *game.Board, pos game.Position) (int, bool) {
+	if m.moving != nil {
+		return m.moving.Length, board.CanMoveShip(m.moving, pos, m.shipOrientation)
+	}
+	ship := m.game.GetCurrentShipForPlacement()
+	if ship == nil {
+		return 0, false
+	}
+	return ship.Length, board.CanPlaceShip(pos, ship.Length, m.shipOrientation)
+}
+
 func renderBattleBoards(m Model) string {
 	playerBoard := renderPlayerBoard(m)
 	enemyBoard := renderEnemyBoard(m)
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, playerBoard, "  ", enemyBoard)
+	boards := lipgloss.JoinHorizontal(lipgloss.Top, playerBoard, "  ", enemyBoard)
+	if !m.game.SalvoMode || m.replay != nil {
+		return boards
+	}
+	return boards + "\n" + renderSalvoReport(m)
+}
+
+// renderSalvoReport lists what every shot of each side's last salvo did
+func renderSalvoReport(m Model) string {
+	var sb strings.Builder
+	for _, side := range []game.Side{m.game.Active, m.game.Active.Opponent()} {
+		salvo := m.game.LastSalvo(side)
+		if len(salvo.Shots) == 0 {
+			continue
+		}
+
+		// Our own shots may be known only as part of a number of hits
+		view := m.game.OpponentView(side)
+		shots := make([]string, len(salvo.Shots))
+		for i, shot := range salvo.Shots {
+			if side == m.game.Active && view.Cell(shot.Target) == game.FogUncertain {
+				shots[i] = salvoMissStyle.Render("? " + shot.Target.String())
+				continue
+			}
+			switch shot.Result {
+			case game.ShotMiss:
+				shots[i] = salvoMissStyle.Render("○ " + shot.Target.String() + " miss")
+			case game.ShotHit:
+				shots[i] = salvoHitStyle.Render("X " + shot.Target.String() + " hit")
+			case game.ShotSunk:
+				shots[i] = salvoHitStyle.Render("# " + shot.Target.String() + " sunk " + shot.Ship)
+			}
+		}
+
+		name := m.game.SideName(side) + "'s"
+		if side == game.PlayerSide && m.game.Mode != game.HotSeat {
+			name = "Your"
+		}
+		sb.WriteString(headerStyle.Render(name + " last salvo:"))
+		sb.WriteString(strings.Join(shots, "  "))
+		if m.game.HitCountsOnly && side == m.game.Active {
+			sb.WriteString("  " + salvoHitStyle.Render(salvo.Summary()))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// renderSalvoShot renders a cell fired at in the last salvo, marked so the
+// salvo stands out on the board
+func renderSalvoShot(shot game.ShotRecord) string {
+	switch shot.Result {
+	case game.ShotHit:
+		return hitStyle.Render("(X)")
+	case game.ShotSunk:
+		return sunkStyle.Render("(#)")
+	}
+	return missStyle.Render("(○)")
 }
 
 func renderPlayerBoard(m Model) string {
@@ -366,23 +636,29 @@ func renderPlayerBoard(m Model) string {
 
 	sb.WriteString(asciiArtStyle.Render(battleshipArt))
 	sb.WriteString("\n")
-	sb.WriteString(headerStyle.Render("Your Fleet"))
+	sb.WriteString(headerStyle.Render(fleetTitle(m, m.game.Active)))
 	sb.WriteString("\n\n")
 
 	// Column headers
 	sb.WriteString("    ")
-	for col := 0; col < m.game.BoardSize; col++ {
+	for col := 0; col < m.game.Cols; col++ {
 		sb.WriteString(fmt.Sprintf(" %c ", 'A'+col))
 	}
 	sb.WriteString("\n")
 
-	// Board
-	for row := 0; row < m.game.BoardSize; row++ {
+	// Board, with the opponent's last salvo marked
+	incoming := m.game.LastSalvo(m.game.Active.Opponent())
+	for row := 0; row < m.game.Rows; row++ {
 		sb.WriteString(fmt.Sprintf("%2d  ", row+1))
 
-		for col := 0; col < m.game.BoardSize; col++ {
+		for col := 0; col < m.game.Cols; col++ {
 			pos := game.Position{Row: row, Col: col}
-			cell := m.game.PlayerBoard.GetCell(pos)
+			if shot, ok := incoming.ShotAt(pos); ok && m.game.SalvoMode {
+				sb.WriteString(renderSalvoShot(shot))
+				continue
+			}
+
+			cell := m.game.Board(m.game.Active).GetCell(pos)
 			cellStr := renderCell(cell, false, false, true)
 			sb.WriteString(cellStr)
 		}
@@ -397,23 +673,30 @@ func renderEnemyBoard(m Model) string {
 
 	sb.WriteString(asciiArtStyle.Render(claudeBattleshipArt))
 	sb.WriteString("\n")
-	sb.WriteString(headerStyle.Render("Captain Claude's Fleet"))
+	sb.WriteString(headerStyle.Render(fleetTitle(m, m.game.Active.Opponent())))
 	sb.WriteString("\n\n")
 
 	// Column headers
 	sb.WriteString("    ")
-	for col := 0; col < m.game.BoardSize; col++ {
+	for col := 0; col < m.game.Cols; col++ {
 		sb.WriteString(fmt.Sprintf(" %c ", 'A'+col))
 	}
 	sb.WriteString("\n")
 
-	// Board
-	for row := 0; row < m.game.BoardSize; row++ {
+	// Board, seen only through the fog so no unhit ship can be drawn, with
+	// our last salvo marked. A replay shows where every shot landed, even when
+	// only the number of hits was announced.
+	fog := m.game.OpponentView(m.game.Active)
+	if m.replay != nil {
+		fog = game.NewFogView(m.game.Board(m.game.Active.Opponent()))
+	}
+	outgoing := m.game.LastSalvo(m.game.Active)
+	for row := 0; row < m.game.Rows; row++ {
 		sb.WriteString(fmt.Sprintf("%2d  ", row+1))
 
-		for col := 0; col < m.game.BoardSize; col++ {
+		for col := 0; col < m.game.Cols; col++ {
 			pos := game.Position{Row: row, Col: col}
-			cell := m.game.ComputerBoard.GetCell(pos)
+			cell := fog.Cell(pos)
 
 			isCursor := row == m.cursorRow && col == m.cursorCol
 
@@ -426,7 +709,28 @@ func renderEnemyBoard(m Model) string {
 				}
 			}
 
-			cellStr := renderCell(cell, isCursor, isQueued, false)
+			if shot, ok := outgoing.ShotAt(pos); ok && m.game.SalvoMode && !isCursor {
+				if cell == game.FogUncertain {
+					sb.WriteString(uncertainStyle.Render("(?)"))
+				} else {
+					sb.WriteString(renderSalvoShot(shot))
+				}
+				continue
+			}
+
+			// A replay reveals the ships that have not been hit yet
+			if m.replay != nil && cell == game.FogUnknown && m.game.Board(m.game.Active.Opponent()).GetCell(pos) == game.ShipCell {
+				sb.WriteString(renderCell(game.ShipCell, false, false, true))
+				continue
+			}
+
+			// Cells next to a sunk ship cannot hold a ship when ships may not touch
+			if fog.RuledOut(pos) && !isCursor && !isQueued {
+				sb.WriteString(blockedStyle.Render(" · "))
+				continue
+			}
+
+			cellStr := renderFogCell(cell, isCursor, isQueued)
 			sb.WriteString(cellStr)
 		}
 		sb.WriteString("\n")
@@ -435,6 +739,27 @@ func renderEnemyBoard(m Model) string {
 	return boardStyle.Render(sb.String())
 }
 
+// renderFogCell renders a cell of an opponent's board as seen through the fog
+func renderFogCell(cell game.FogCell, isCursor bool, isQueued bool) string {
+	switch cell {
+	case game.FogHit:
+		return renderCell(game.Hit, isCursor, false, false)
+	case game.FogMiss:
+		return renderCell(game.Miss, isCursor, false, false)
+	case game.FogSunk:
+		if isCursor {
+			return grayCursorStyle.Render("[#]")
+		}
+		return sunkStyle.Render(" # ")
+	case game.FogUncertain:
+		if isCursor {
+			return grayCursorStyle.Render("[?]")
+		}
+		return uncertainStyle.Render(" ? ")
+	}
+	return renderCell(game.Empty, isCursor, isQueued, false)
+}
+
 func renderCell(cell game.CellState, isCursor bool, isPreview bool, showShips bool) string {
 	symbol := "~"
 
@@ -453,6 +778,9 @@ func renderCell(cell game.CellState, isCursor bool, isPreview bool, showShips bo
 	case game.ShipCell:
 		if showShips {
 			symbol = "█"
+			if isCursor {
+				return cursorStyle.Render("[" + symbol + "]")
+			}
 			return shipStyle.Render(" " + symbol + " ")
 		}
 		// For hidden enemy ships, show cursor if applicable
@@ -489,7 +817,18 @@ func renderGameOver(m Model) string {
 
 	// Game over message
 	gameOverMsg := ""
-	if m.game.Winner == "Player" {
+	if m.game.Mode == game.HotSeat {
+		gameOverMsg = fmt.Sprintf("🎉 %s WINS! 🎉", strings.ToUpper(m.game.Winner))
+	} else if m.game.Mode == game.Network {
+		switch m.game.Winner {
+		case "":
+			gameOverMsg = "⚠ GAME ABANDONED ⚠"
+		case "Player":
+			gameOverMsg = "🎉 VICTORY! You sunk your opponent's fleet! 🎉"
+		default:
+			gameOverMsg = "💥 DEFEAT! Your opponent won! 💥"
+		}
+	} else if m.game.Winner == "Player" {
 		gameOverMsg = "🎉 VICTORY! You sunk Captain Claude's fleet! 🎉"
 	} else {
 		gameOverMsg = "💥 DEFEAT! All your ships were sunk! 💥"
@@ -529,8 +868,50 @@ func renderGameOver(m Model) string {
 		sb.WriteString("\n")
 	}
 
-	// Show instructions
-	sb.WriteString(helpStyle.Render("Press R to restart | Press Q to quit"))
+	// A network game cannot be replayed from a seed or restarted
+	if m.game.Mode == game.Network {
+		if m.net.verdict != "" {
+			sb.WriteString(messageStyle.Render(m.net.verdict))
+			sb.WriteString("\n\n")
+		} else if m.net.remoteReady {
+			sb.WriteString(helpStyle.Render("Waiting for your opponent to reveal their fleet..."))
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(helpStyle.Render("Press Q to quit"))
+		return sb.String()
+	}
+
+	// Show the seed so the game can be replayed
+	sb.WriteString(helpStyle.Render(fmt.Sprintf("Seed: %d (replay with --seed %d)", m.game.Seed, m.game.Seed)))
+	sb.WriteString("\n")
+	if m.replayPath != "" {
+		sb.WriteString(helpStyle.Render("Watch this game again with: battleship replay " + m.replayPath))
+		sb.WriteString("\n")
+	}
+	if m.replayMessage != "" {
+		sb.WriteString(messageStyle.Render(m.replayMessage))
+		sb.WriteString("\n")
+	}
+	if m.exportMessage != "" {
+		sb.WriteString(messageStyle.Render(m.exportMessage))
+		sb.WriteString("\n")
+	}
+
+	// What next
+	options := []string{"▶  Rematch with same settings", "▶  Rematch with the same fleet layout", "◀  Back to menu"}
+	for i, option := range options {
+		if i == m.gameOverSelection {
+			sb.WriteString(selectedMenuItemStyle.Render(option))
+		} else {
+			sb.WriteString(menuItemStyle.Render(option))
+		}
+		sb.WriteString("\n")
+	}
+	help := "Up/Down and Enter to choose | Press Q to quit"
+	if m.replayPath != "" {
+		help = "Up/Down and Enter to choose | E to export the game as text | Press Q to quit"
+	}
+	sb.WriteString(helpStyle.Render(help))
 
 	return sb.String()
 }
@@ -545,15 +926,214 @@ func renderHelp(m Model) string {
 		sb.WriteString("  Arrow Keys/WASD - Move cursor\n")
 		sb.WriteString("  O - Toggle orientation (Horizontal/Vertical)\n")
 		sb.WriteString("  Space/Enter - Place ship\n")
+		sb.WriteString("  M - Pick up a placed ship to move or rotate it\n")
+		sb.WriteString("  U/Y - Undo/Redo\n")
+		sb.WriteString("  X - Randomize my fleet\n")
+	case game.FleetReviewPhase:
+		sb.WriteString("  Arrow Keys/WASD - Move cursor\n")
+		sb.WriteString("  M - Pick up a ship to move or rotate it\n")
+		sb.WriteString("  O - Toggle orientation of the ship being moved\n")
+		sb.WriteString("  Space/Enter - Put the ship down\n")
+		sb.WriteString("  U/Y - Undo/Redo\n")
+		sb.WriteString("  X - Randomize my fleet\n")
+		sb.WriteString("  C - Confirm fleet and start\n")
 	case game.PlayerTurnPhase, game.ComputerTurnPhase:
 		sb.WriteString("  Arrow Keys/WASD - Move cursor\n")
 		sb.WriteString("  Space/Enter - Fire!\n")
 	case game.GameOverPhase:
-		sb.WriteString("  R - Restart game\n")
+		if m.net == nil {
+			sb.WriteString("  Up/Down, Enter - Rematch or go back to the menu\n")
+			sb.WriteString("  R - Rematch with same settings\n")
+		}
 	}
 
 	sb.WriteString("  H - Toggle help\n")
-	sb.WriteString("  Q - Quit\n")
+	if m.net != nil && m.game.Phase != game.GameOverPhase {
+		sb.WriteString("  Q - Resign and quit\n")
+	} else {
+		sb.WriteString("  Q - Quit\n")
+	}
 
 	return helpStyle.Render(sb.String())
 }
+
+// renderStatistics renders the player's statistics against Captain Claude
+func renderStatistics(m Model) string {
+	var sb strings.Builder
+	stats := m.profile.Stats
+
+	sb.WriteString(menuTitleStyle.Render("📊  S T A T I S T I C S  📊"))
+	sb.WriteString("\n\n")
+
+	if stats.Played == 0 {
+		sb.WriteString(menuItemStyle.Render("No games played against Captain Claude yet."))
+		sb.WriteString("\n\n")
+		sb.WriteString(helpStyle.Render("Press Esc or Enter to go back"))
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, sb.String())
+	}
+
+	overall := &Record{Wins: stats.Wins, Losses: stats.Losses}
+	summary := statsTable("", "").Rows(
+		[]string{"Games played", fmt.Sprint(stats.Played)},
+		[]string{"Wins / losses", fmt.Sprintf("%d / %d (%.0f%%)", stats.Wins, stats.Losses, 100*overall.WinRate())},
+		[]string{"Average shots to win", fmt.Sprintf("%.1f", stats.AverageShotsToWin())},
+		[]string{"Best accuracy", fmt.Sprintf("%.0f%%", 100*stats.BestAccuracy)},
+		[]string{"Longest win streak", fmt.Sprint(stats.LongestStreak)},
+		[]string{"Current win streak", fmt.Sprint(stats.CurrentStreak)},
+	)
+
+	overview := lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, summary.Render(), "  ", recordTable("Difficulty", stats.ByDifficulty)),
+		lipgloss.JoinHorizontal(lipgloss.Top, recordTable("Board", stats.ByBoardSize), "  ", recordTable("Firing", stats.ByFiringMode)))
+
+	recent := statsTable("Date", "Result", "Difficulty", "Board", "Fleet", "Firing", "Shots", "Accuracy")
+	for _, record := range stats.Recent {
+		result := "Lost"
+		if record.Won {
+			result = "Won"
+		}
+		recent.Row(record.Date.Format("2006-01-02"), result, record.Difficulty, record.BoardSize(),
+			record.Fleet, record.FiringMode(), fmt.Sprint(record.Shots), fmt.Sprintf("%.0f%%", 100*record.Accuracy))
+	}
+
+	sb.WriteString(overview)
+	sb.WriteString("\n\n")
+	sb.WriteString(headerStyle.Render("Recent games"))
+	sb.WriteString("\n")
+	sb.WriteString(recent.Render())
+	sb.WriteString("\n")
+	sb.WriteString(helpStyle.Render("Press Esc or Enter to go back"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, sb.String())
+}
+
+// statsTable creates a table in the style of the statistics screen. Empty
+// headers leave the header row out.
+func statsTable(headers ...string) *table.Table {
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(oceanBlue)).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return lipgloss.NewStyle().Foreground(titleCyan).Bold(true).Padding(0, 1)
+			}
+			return lipgloss.NewStyle().Foreground(lipgloss.Color("#CCCCCC")).Padding(0, 1)
+		})
+	if strings.Join(headers, "") != "" {
+		t.Headers(headers...)
+	}
+	return t
+}
+
+// recordTable renders wins and losses broken down by the given category,
+// in alphabetical order
+func recordTable(category string, records map[string]*Record) string {
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	t := statsTable(category, "Played", "Won", "Win %")
+	for _, key := range keys {
+		r := records[key]
+		t.Row(key, fmt.Sprint(r.Played()), fmt.Sprint(r.Wins), fmt.Sprintf("%.0f%%", 100*r.WinRate()))
+	}
+	return t.Render()
+}
+
+// achievementsPerPage is how many achievements fit on the achievements screen
+// in a terminal of the given height
+func achievementsPerPage(height int) int {
+	// Each achievement takes three lines, and the title, scroll markers and
+	// help take up about ten more
+	return max(1, (height-10)/3)
+}
+
+// renderAchievements renders a scrollable list of every achievement
+func renderAchievements(m Model) string {
+	var sb strings.Builder
+	all := m.profile.Achievements.GetAll()
+
+	unlocked := 0
+	for _, ach := range all {
+		if ach.Unlocked() {
+			unlocked++
+		}
+	}
+
+	sb.WriteString(menuTitleStyle.Render("🏆  A C H I E V E M E N T S  🏆"))
+	sb.WriteString("\n")
+	sb.WriteString(menuItemStyle.Render(fmt.Sprintf("%d of %d unlocked", unlocked, len(all))))
+	sb.WriteString("\n\n")
+
+	start := min(m.achievementScroll, len(all))
+	end := min(start+achievementsPerPage(m.height), len(all))
+	if start > 0 {
+		sb.WriteString(helpStyle.Render(fmt.Sprintf("▲ %d more", start)))
+	}
+	sb.WriteString("\n")
+
+	for _, ach := range all[start:end] {
+		if ach.Unlocked() {
+			sb.WriteString(unlockedAchievementStyle.Render("⭐ " + ach.Name))
+			sb.WriteString("\n")
+			sb.WriteString(menuItemStyle.Render("   " + ach.Description + " · " + describeUnlock(ach.Unlock)))
+		} else {
+			sb.WriteString(lockedAchievementStyle.Render("🔒 " + ach.Name))
+			sb.WriteString("\n")
+			sb.WriteString(lockedAchievementStyle.Render("   " + ach.Description))
+		}
+		sb.WriteString("\n\n")
+	}
+
+	if end < len(all) {
+		sb.WriteString(helpStyle.Render(fmt.Sprintf("▼ %d more", len(all)-end)))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(helpStyle.Render("Use ↑/↓ to scroll, Esc or Enter to go back"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, sb.String())
+}
+
+// describeUnlock says when an achievement was unlocked and the settings of
+// the game that unlocked it
+func describeUnlock(u *Unlock) string {
+	if u.Date.IsZero() {
+		return "Unlocked before dates were recorded"
+	}
+
+	desc := fmt.Sprintf("Unlocked %s · %s · %dx%d · %s", u.Date.Format("2006-01-02"), u.Difficulty, u.Rows, u.Cols, u.Fleet)
+	if u.Salvo {
+		desc += " · Salvo"
+	}
+	return desc
+}
+
+// renderReplay renders a recorded game as it stood after the current turn,
+// with both fleets revealed
+func renderReplay(m Model) string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render("⚓ BATTLESHIP REPLAY ⚓"))
+	sb.WriteString("\n\n")
+
+	progress := fmt.Sprintf("Move %d of %d", m.replay.turn, m.replay.replay.Turns())
+	if m.game.Phase == game.GameOverPhase {
+		progress += " · Winner: " + m.game.Winner
+	}
+	sb.WriteString(headerStyle.Render(progress))
+	sb.WriteString("\n")
+	sb.WriteString(messageStyle.Render(m.game.LastMessage))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(renderBattleBoards(m))
+	sb.WriteString("\n")
+
+	if m.showHelp {
+		sb.WriteString("\n")
+		sb.WriteString(helpStyle.Render("Controls:\n  →/Space - Next move\n  ← - Previous move\n  Home/End - First/Last move\n  H - Toggle help\n  Q/Esc - Quit"))
+	}
+
+	return sb.String()
+}